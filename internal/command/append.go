@@ -0,0 +1,30 @@
+package command
+
+// Appends a value to the string stored at key, creating the key if needed.
+// APPEND key value
+// https://redis.io/commands/append
+type Append struct {
+	baseCmd
+	key   string
+	value []byte
+}
+
+func parseAppend(b baseCmd) (*Append, error) {
+	cmd := &Append{baseCmd: b}
+	if len(cmd.args) != 2 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+	cmd.value = cmd.args[1]
+	return cmd, nil
+}
+
+func (cmd *Append) Run(w Writer, red Redka) (any, error) {
+	n, err := red.Str().Append(cmd.key, cmd.value)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteInt(n)
+	return n, nil
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Counts the set bits in a string, optionally restricted to a byte or bit range.
+// BITCOUNT key [start end [BYTE | BIT]]
+// https://redis.io/commands/bitcount
+type BitCount struct {
+	baseCmd
+	key   string
+	start int64
+	end   int64
+	byBit bool
+}
+
+func parseBitCount(b baseCmd) (*BitCount, error) {
+	cmd := &BitCount{baseCmd: b, start: 0, end: -1}
+
+	switch len(cmd.args) {
+	case 1:
+		// whole string
+	case 3, 4:
+		start, err := strconv.ParseInt(string(cmd.args[1]), 10, 64)
+		if err != nil {
+			return cmd, ErrInvalidInt
+		}
+		end, err := strconv.ParseInt(string(cmd.args[2]), 10, 64)
+		if err != nil {
+			return cmd, ErrInvalidInt
+		}
+		cmd.start, cmd.end = start, end
+
+		if len(cmd.args) == 4 {
+			switch strings.ToUpper(string(cmd.args[3])) {
+			case "BYTE":
+				cmd.byBit = false
+			case "BIT":
+				cmd.byBit = true
+			default:
+				return cmd, ErrInvalidArgNum
+			}
+		}
+	default:
+		return cmd, ErrInvalidArgNum
+	}
+
+	cmd.key = string(cmd.args[0])
+	return cmd, nil
+}
+
+func (cmd *BitCount) Run(w Writer, red Redka) (any, error) {
+	n, err := red.Str().BitCount(cmd.key, cmd.start, cmd.end, cmd.byBit)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteInt(int(n))
+	return n, nil
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/nalgeon/redka/internal/rstring"
+)
+
+// Performs a bitwise operation between multiple strings and stores the result.
+// BITOP AND|OR|XOR|NOT destkey key [key ...]
+// https://redis.io/commands/bitop
+type BitOp struct {
+	baseCmd
+	op      rstring.BitOp
+	destKey string
+	srcKeys []string
+}
+
+func parseBitOp(b baseCmd) (*BitOp, error) {
+	cmd := &BitOp{baseCmd: b}
+	if len(cmd.args) < 3 {
+		return cmd, ErrInvalidArgNum
+	}
+
+	switch strings.ToUpper(string(cmd.args[0])) {
+	case "AND":
+		cmd.op = rstring.BitAnd
+	case "OR":
+		cmd.op = rstring.BitOr
+	case "XOR":
+		cmd.op = rstring.BitXor
+	case "NOT":
+		cmd.op = rstring.BitNot
+	default:
+		return cmd, ErrInvalidArgNum
+	}
+
+	cmd.destKey = string(cmd.args[1])
+	cmd.srcKeys = make([]string, len(cmd.args)-2)
+	for i, k := range cmd.args[2:] {
+		cmd.srcKeys[i] = string(k)
+	}
+
+	if cmd.op == rstring.BitNot && len(cmd.srcKeys) != 1 {
+		return cmd, ErrInvalidArgNum
+	}
+
+	return cmd, nil
+}
+
+func (cmd *BitOp) Run(w Writer, red Redka) (any, error) {
+	n, err := red.Str().BitOp(cmd.op, cmd.destKey, cmd.srcKeys...)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteInt(int(n))
+	return n, nil
+}
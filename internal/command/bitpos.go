@@ -0,0 +1,73 @@
+package command
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Returns the position of the first bit set to bit (0 or 1) in a string,
+// optionally restricted to a byte or bit range.
+// BITPOS key bit [start [end [BYTE | BIT]]]
+// https://redis.io/commands/bitpos
+type BitPos struct {
+	baseCmd
+	key    string
+	bit    int
+	start  int64
+	end    int64
+	hasEnd bool
+	byBit  bool
+}
+
+func parseBitPos(b baseCmd) (*BitPos, error) {
+	cmd := &BitPos{baseCmd: b, start: 0, end: -1}
+
+	if len(cmd.args) < 2 || len(cmd.args) > 5 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+
+	bit, err := strconv.Atoi(string(cmd.args[1]))
+	if err != nil || (bit != 0 && bit != 1) {
+		return cmd, ErrInvalidInt
+	}
+	cmd.bit = bit
+
+	if len(cmd.args) >= 3 {
+		start, err := strconv.ParseInt(string(cmd.args[2]), 10, 64)
+		if err != nil {
+			return cmd, ErrInvalidInt
+		}
+		cmd.start = start
+	}
+	if len(cmd.args) >= 4 {
+		end, err := strconv.ParseInt(string(cmd.args[3]), 10, 64)
+		if err != nil {
+			return cmd, ErrInvalidInt
+		}
+		cmd.end = end
+		cmd.hasEnd = true
+	}
+	if len(cmd.args) == 5 {
+		switch strings.ToUpper(string(cmd.args[4])) {
+		case "BYTE":
+			cmd.byBit = false
+		case "BIT":
+			cmd.byBit = true
+		default:
+			return cmd, ErrInvalidArgNum
+		}
+	}
+
+	return cmd, nil
+}
+
+func (cmd *BitPos) Run(w Writer, red Redka) (any, error) {
+	pos, err := red.Str().BitPos(cmd.key, cmd.bit, cmd.start, cmd.end, cmd.byBit, cmd.hasEnd)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteInt(int(pos))
+	return pos, nil
+}
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"strconv"
+	"time"
+)
+
+// Checks whether a rate limit has been exceeded and consumes from it if not.
+// CL.THROTTLE key max_burst count_per_period period [quantity]
+// https://github.com/brandur/redis-cell
+type ClThrottle struct {
+	baseCmd
+	key            string
+	maxBurst       int
+	countPerPeriod int
+	period         time.Duration
+	quantity       int
+}
+
+func parseClThrottle(b baseCmd) (*ClThrottle, error) {
+	cmd := &ClThrottle{baseCmd: b, quantity: 1}
+	if len(cmd.args) != 4 && len(cmd.args) != 5 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+
+	maxBurst, err := strconv.Atoi(string(cmd.args[1]))
+	if err != nil {
+		return cmd, ErrInvalidInt
+	}
+	cmd.maxBurst = maxBurst
+
+	countPerPeriod, err := strconv.Atoi(string(cmd.args[2]))
+	if err != nil {
+		return cmd, ErrInvalidInt
+	}
+	cmd.countPerPeriod = countPerPeriod
+
+	periodSec, err := strconv.Atoi(string(cmd.args[3]))
+	if err != nil {
+		return cmd, ErrInvalidInt
+	}
+	cmd.period = time.Duration(periodSec) * time.Second
+
+	if len(cmd.args) == 5 {
+		quantity, err := strconv.Atoi(string(cmd.args[4]))
+		if err != nil {
+			return cmd, ErrInvalidInt
+		}
+		cmd.quantity = quantity
+	}
+
+	return cmd, nil
+}
+
+func (cmd *ClThrottle) Run(w Writer, red Redka) (any, error) {
+	limited, remaining, retryAfter, resetAfter, err := red.Limit().Limit(
+		cmd.key, cmd.maxBurst, cmd.countPerPeriod, cmd.period, cmd.quantity,
+	)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+
+	limitedInt := 0
+	if limited {
+		limitedInt = 1
+	}
+	retrySec := -1
+	if limited {
+		retrySec = int(retryAfter / time.Second)
+	}
+
+	w.WriteArray(5)
+	w.WriteInt(limitedInt)
+	w.WriteInt(cmd.maxBurst + 1)
+	w.WriteInt(remaining)
+	w.WriteInt(retrySec)
+	w.WriteInt(int(resetAfter / time.Second))
+
+	return !limited, nil
+}
@@ -0,0 +1,131 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nalgeon/redka/internal/rlimit"
+	"github.com/nalgeon/redka/internal/rset"
+	"github.com/nalgeon/redka/internal/rstring"
+)
+
+// Errors returned while parsing a command's arguments, before it ever
+// reaches a repository.
+var (
+	ErrInvalidArgNum = fmt.Errorf("ERR wrong number of arguments")
+	ErrInvalidInt    = fmt.Errorf("ERR value is not an integer or out of range")
+)
+
+// Cmd is a single parsed RESP command, ready to run against a Redka
+// instance.
+type Cmd interface {
+	Run(w Writer, red Redka) (any, error)
+}
+
+// Writer writes a command's RESP reply.
+type Writer interface {
+	WriteError(err error)
+	WriteOK()
+	WriteNil()
+	WriteInt(n int)
+	WriteBulk(b []byte)
+	WriteBulkString(s string)
+	WriteArray(n int)
+}
+
+// Redka is the subset of redka's repository accessors a command's Run
+// method needs. Only the accessors the commands in this package use
+// are listed here; the full Redka instance also exposes the other
+// data type repositories (Hash, List, ZSet, Key, ...).
+type Redka interface {
+	// Str returns the string repository.
+	Str() *rstring.DB
+	// Set returns the set repository.
+	Set() *rset.DB
+	// Limit returns the GCRA rate limiter.
+	Limit() *rlimit.DB
+}
+
+// baseCmd holds the raw, unparsed representation shared by every Cmd:
+// its name (for error messages) and its arguments (everything after
+// the command name itself).
+type baseCmd struct {
+	name string
+	args [][]byte
+}
+
+// Error wraps err with the command's name, so a client can tell which
+// command a reply error came from.
+func (cmd baseCmd) Error(err error) error {
+	return fmt.Errorf("%s: %w", strings.ToLower(cmd.name), err)
+}
+
+// equalFold reports whether b, compared case-insensitively, equals s.
+// Used to match option keywords (MATCH, COUNT, ...) regardless of case.
+func equalFold(b []byte, s string) bool {
+	return strings.EqualFold(string(b), s)
+}
+
+// Parse parses a command's name and arguments into a Cmd ready to Run
+// against a Redka instance.
+func Parse(name string, args [][]byte) (Cmd, error) {
+	b := baseCmd{name: name, args: args}
+	switch strings.ToUpper(name) {
+	case "APPEND":
+		return parseAppend(b)
+	case "BITCOUNT":
+		return parseBitCount(b)
+	case "BITOP":
+		return parseBitOp(b)
+	case "BITPOS":
+		return parseBitPos(b)
+	case "CL.THROTTLE":
+		return parseClThrottle(b)
+	case "GETBIT":
+		return parseGetBit(b)
+	case "SETBIT":
+		return parseSetBit(b)
+	case "IDX.ASCEND":
+		return parseIdxAscend(b)
+	case "IDX.CREATE":
+		return parseIdxCreate(b)
+	case "IDX.DESCEND":
+		return parseIdxDescend(b)
+	case "IDX.DROP":
+		return parseIdxDrop(b)
+	case "IDX.LIST":
+		return parseIdxList(b)
+	case "IDX.RANGE":
+		return parseIdxRange(b)
+	case "SADD":
+		return parseSAdd(b)
+	case "SCARD":
+		return parseSCard(b)
+	case "SDIFF":
+		return parseSDiff(b)
+	case "SDIFFSTORE":
+		return parseSDiffStore(b)
+	case "SINTER":
+		return parseSInter(b)
+	case "SINTERSTORE":
+		return parseSInterStore(b)
+	case "SISMEMBER":
+		return parseSIsMember(b)
+	case "SMEMBERS":
+		return parseSMembers(b)
+	case "SPOP":
+		return parseSPop(b)
+	case "SRANDMEMBER":
+		return parseSRandMember(b)
+	case "SREM":
+		return parseSRem(b)
+	case "SSCAN":
+		return parseSScan(b)
+	case "SUNION":
+		return parseSUnion(b)
+	case "SUNIONSTORE":
+		return parseSUnionStore(b)
+	default:
+		return nil, fmt.Errorf("ERR unknown command '%s'", name)
+	}
+}
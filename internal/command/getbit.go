@@ -0,0 +1,38 @@
+package command
+
+import "strconv"
+
+// Returns the bit value at offset in the string value stored at key.
+// GETBIT key offset
+// https://redis.io/commands/getbit
+type GetBit struct {
+	baseCmd
+	key    string
+	offset uint64
+}
+
+func parseGetBit(b baseCmd) (*GetBit, error) {
+	cmd := &GetBit{baseCmd: b}
+	if len(cmd.args) != 2 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+
+	offset, err := strconv.ParseUint(string(cmd.args[1]), 10, 64)
+	if err != nil {
+		return cmd, ErrInvalidInt
+	}
+	cmd.offset = offset
+
+	return cmd, nil
+}
+
+func (cmd *GetBit) Run(w Writer, red Redka) (any, error) {
+	val, err := red.Str().GetBit(cmd.key, cmd.offset)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteInt(val)
+	return val, nil
+}
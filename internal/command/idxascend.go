@@ -0,0 +1,43 @@
+package command
+
+import "github.com/nalgeon/redka/internal/core"
+
+// Iterates over the keys of an index in ascending sort-key order.
+// IDX.ASCEND name [pivot]
+type IdxAscend struct {
+	baseCmd
+	name  string
+	pivot string
+}
+
+func parseIdxAscend(b baseCmd) (*IdxAscend, error) {
+	cmd := &IdxAscend{baseCmd: b}
+	if len(cmd.args) != 1 && len(cmd.args) != 2 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.name = string(cmd.args[0])
+	if len(cmd.args) == 2 {
+		cmd.pivot = string(cmd.args[1])
+	}
+	return cmd, nil
+}
+
+func (cmd *IdxAscend) Run(w Writer, red Redka) (any, error) {
+	var keys []string
+	var vals [][]byte
+	err := red.Str().Ascend(cmd.name, cmd.pivot, func(key string, val core.Value) bool {
+		keys = append(keys, key)
+		vals = append(vals, val.Bytes())
+		return true
+	})
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteArray(len(keys) * 2)
+	for i, key := range keys {
+		w.WriteBulkString(key)
+		w.WriteBulk(vals[i])
+	}
+	return keys, nil
+}
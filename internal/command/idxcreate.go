@@ -0,0 +1,77 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/nalgeon/redka/internal/rstring"
+)
+
+// Creates a secondary index over string keys matching a pattern.
+// IDX.CREATE name pattern (JSON path | WHOLE | SPATIAL latpath lonpath) [STRING | INT | FLOAT] [DESC]
+// See the buntdb index model this command is based on.
+type IdxCreate struct {
+	baseCmd
+	name    string
+	pattern string
+	opts    rstring.IndexOptions
+}
+
+func parseIdxCreate(b baseCmd) (*IdxCreate, error) {
+	cmd := &IdxCreate{baseCmd: b}
+	if len(cmd.args) < 3 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.name = string(cmd.args[0])
+	cmd.pattern = string(cmd.args[1])
+
+	rest := cmd.args[2:]
+	switch strings.ToUpper(string(rest[0])) {
+	case "WHOLE":
+		cmd.opts.Projection = rstring.Whole()
+		rest = rest[1:]
+	case "SPATIAL":
+		if len(rest) < 3 {
+			return cmd, ErrInvalidArgNum
+		}
+		cmd.opts.Projection = rstring.Spatial(string(rest[1]), string(rest[2]))
+		rest = rest[3:]
+	default:
+		cmd.opts.Projection = rstring.JSONPath(string(rest[0]))
+		rest = rest[1:]
+	}
+
+	cmp := rstring.String()
+	if len(rest) > 0 {
+		switch strings.ToUpper(string(rest[0])) {
+		case "STRING":
+			cmp = rstring.String()
+			rest = rest[1:]
+		case "INT":
+			cmp = rstring.Int()
+			rest = rest[1:]
+		case "FLOAT":
+			cmp = rstring.Float()
+			rest = rest[1:]
+		}
+	}
+	if len(rest) > 0 && strings.ToUpper(string(rest[0])) == "DESC" {
+		cmp = rstring.Desc(cmp)
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.opts.Comparator = cmp
+
+	return cmd, nil
+}
+
+func (cmd *IdxCreate) Run(w Writer, red Redka) (any, error) {
+	err := red.Str().CreateIndex(cmd.name, cmd.pattern, cmd.opts)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteOK()
+	return true, nil
+}
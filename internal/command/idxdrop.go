@@ -0,0 +1,27 @@
+package command
+
+// Removes a secondary index created by IDX.CREATE.
+// IDX.DROP name
+type IdxDrop struct {
+	baseCmd
+	name string
+}
+
+func parseIdxDrop(b baseCmd) (*IdxDrop, error) {
+	cmd := &IdxDrop{baseCmd: b}
+	if len(cmd.args) != 1 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.name = string(cmd.args[0])
+	return cmd, nil
+}
+
+func (cmd *IdxDrop) Run(w Writer, red Redka) (any, error) {
+	err := red.Str().DropIndex(cmd.name)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteOK()
+	return true, nil
+}
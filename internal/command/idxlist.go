@@ -0,0 +1,28 @@
+package command
+
+// Lists the names of all secondary indexes.
+// IDX.LIST
+type IdxList struct {
+	baseCmd
+}
+
+func parseIdxList(b baseCmd) (*IdxList, error) {
+	cmd := &IdxList{baseCmd: b}
+	if len(cmd.args) != 0 {
+		return cmd, ErrInvalidArgNum
+	}
+	return cmd, nil
+}
+
+func (cmd *IdxList) Run(w Writer, red Redka) (any, error) {
+	names, err := red.Str().Indexes()
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteArray(len(names))
+	for _, name := range names {
+		w.WriteBulkString(name)
+	}
+	return names, nil
+}
@@ -0,0 +1,44 @@
+package command
+
+import "github.com/nalgeon/redka/internal/core"
+
+// Iterates over the keys of an index whose sort key falls within
+// [start,end), in the index's natural order (descending for a DESC index).
+// IDX.RANGE name start end
+type IdxRange struct {
+	baseCmd
+	name  string
+	start string
+	end   string
+}
+
+func parseIdxRange(b baseCmd) (*IdxRange, error) {
+	cmd := &IdxRange{baseCmd: b}
+	if len(cmd.args) != 3 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.name = string(cmd.args[0])
+	cmd.start = string(cmd.args[1])
+	cmd.end = string(cmd.args[2])
+	return cmd, nil
+}
+
+func (cmd *IdxRange) Run(w Writer, red Redka) (any, error) {
+	var keys []string
+	var vals [][]byte
+	err := red.Str().AscendRange(cmd.name, cmd.start, cmd.end, func(key string, val core.Value) bool {
+		keys = append(keys, key)
+		vals = append(vals, val.Bytes())
+		return true
+	})
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteArray(len(keys) * 2)
+	for i, key := range keys {
+		w.WriteBulkString(key)
+		w.WriteBulk(vals[i])
+	}
+	return keys, nil
+}
@@ -0,0 +1,33 @@
+package command
+
+// Adds the specified members to the set stored at key.
+// SADD key member [member ...]
+// https://redis.io/commands/sadd
+type SAdd struct {
+	baseCmd
+	key     string
+	members []any
+}
+
+func parseSAdd(b baseCmd) (*SAdd, error) {
+	cmd := &SAdd{baseCmd: b}
+	if len(cmd.args) < 2 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+	cmd.members = make([]any, len(cmd.args)-1)
+	for i, m := range cmd.args[1:] {
+		cmd.members[i] = m
+	}
+	return cmd, nil
+}
+
+func (cmd *SAdd) Run(w Writer, red Redka) (any, error) {
+	n, err := red.Set().Add(cmd.key, cmd.members...)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteInt(n)
+	return n, nil
+}
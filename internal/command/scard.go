@@ -0,0 +1,28 @@
+package command
+
+// Returns the number of members in the set stored at key.
+// SCARD key
+// https://redis.io/commands/scard
+type SCard struct {
+	baseCmd
+	key string
+}
+
+func parseSCard(b baseCmd) (*SCard, error) {
+	cmd := &SCard{baseCmd: b}
+	if len(cmd.args) != 1 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+	return cmd, nil
+}
+
+func (cmd *SCard) Run(w Writer, red Redka) (any, error) {
+	n, err := red.Set().Card(cmd.key)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteInt(n)
+	return n, nil
+}
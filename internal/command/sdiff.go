@@ -0,0 +1,35 @@
+package command
+
+// Returns the members of the set stored at the first key that are not
+// present in any of the other sets.
+// SDIFF key [key ...]
+// https://redis.io/commands/sdiff
+type SDiff struct {
+	baseCmd
+	keys []string
+}
+
+func parseSDiff(b baseCmd) (*SDiff, error) {
+	cmd := &SDiff{baseCmd: b}
+	if len(cmd.args) < 1 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.keys = make([]string, len(cmd.args))
+	for i, k := range cmd.args {
+		cmd.keys[i] = string(k)
+	}
+	return cmd, nil
+}
+
+func (cmd *SDiff) Run(w Writer, red Redka) (any, error) {
+	elems, err := red.Set().Diff(cmd.keys...)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteArray(len(elems))
+	for _, elem := range elems {
+		w.WriteBulk(elem.Bytes())
+	}
+	return elems, nil
+}
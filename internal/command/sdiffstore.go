@@ -0,0 +1,34 @@
+package command
+
+// Stores the difference between the set stored at the first key and the
+// rest of the sets into destkey.
+// SDIFFSTORE destkey key [key ...]
+// https://redis.io/commands/sdiffstore
+type SDiffStore struct {
+	baseCmd
+	destKey string
+	srcKeys []string
+}
+
+func parseSDiffStore(b baseCmd) (*SDiffStore, error) {
+	cmd := &SDiffStore{baseCmd: b}
+	if len(cmd.args) < 2 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.destKey = string(cmd.args[0])
+	cmd.srcKeys = make([]string, len(cmd.args)-1)
+	for i, k := range cmd.args[1:] {
+		cmd.srcKeys[i] = string(k)
+	}
+	return cmd, nil
+}
+
+func (cmd *SDiffStore) Run(w Writer, red Redka) (any, error) {
+	n, err := red.Set().DiffStore(cmd.destKey, cmd.srcKeys...)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteInt(n)
+	return n, nil
+}
@@ -0,0 +1,45 @@
+package command
+
+import "strconv"
+
+// Sets or clears the bit at offset in the string value stored at key.
+// SETBIT key offset value
+// https://redis.io/commands/setbit
+type SetBit struct {
+	baseCmd
+	key    string
+	offset uint64
+	value  int
+}
+
+func parseSetBit(b baseCmd) (*SetBit, error) {
+	cmd := &SetBit{baseCmd: b}
+	if len(cmd.args) != 3 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+
+	offset, err := strconv.ParseUint(string(cmd.args[1]), 10, 64)
+	if err != nil {
+		return cmd, ErrInvalidInt
+	}
+	cmd.offset = offset
+
+	value, err := strconv.Atoi(string(cmd.args[2]))
+	if err != nil || (value != 0 && value != 1) {
+		return cmd, ErrInvalidInt
+	}
+	cmd.value = value
+
+	return cmd, nil
+}
+
+func (cmd *SetBit) Run(w Writer, red Redka) (any, error) {
+	old, err := red.Str().SetBit(cmd.key, cmd.offset, cmd.value)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteInt(old)
+	return old, nil
+}
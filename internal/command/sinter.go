@@ -0,0 +1,34 @@
+package command
+
+// Returns the members of the intersection of the sets stored at the given keys.
+// SINTER key [key ...]
+// https://redis.io/commands/sinter
+type SInter struct {
+	baseCmd
+	keys []string
+}
+
+func parseSInter(b baseCmd) (*SInter, error) {
+	cmd := &SInter{baseCmd: b}
+	if len(cmd.args) < 1 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.keys = make([]string, len(cmd.args))
+	for i, k := range cmd.args {
+		cmd.keys[i] = string(k)
+	}
+	return cmd, nil
+}
+
+func (cmd *SInter) Run(w Writer, red Redka) (any, error) {
+	elems, err := red.Set().Inter(cmd.keys...)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteArray(len(elems))
+	for _, elem := range elems {
+		w.WriteBulk(elem.Bytes())
+	}
+	return elems, nil
+}
@@ -0,0 +1,33 @@
+package command
+
+// Stores the intersection of the sets stored at the given keys into destkey.
+// SINTERSTORE destkey key [key ...]
+// https://redis.io/commands/sinterstore
+type SInterStore struct {
+	baseCmd
+	destKey string
+	srcKeys []string
+}
+
+func parseSInterStore(b baseCmd) (*SInterStore, error) {
+	cmd := &SInterStore{baseCmd: b}
+	if len(cmd.args) < 2 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.destKey = string(cmd.args[0])
+	cmd.srcKeys = make([]string, len(cmd.args)-1)
+	for i, k := range cmd.args[1:] {
+		cmd.srcKeys[i] = string(k)
+	}
+	return cmd, nil
+}
+
+func (cmd *SInterStore) Run(w Writer, red Redka) (any, error) {
+	n, err := red.Set().InterStore(cmd.destKey, cmd.srcKeys...)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteInt(n)
+	return n, nil
+}
@@ -0,0 +1,34 @@
+package command
+
+// Reports whether member is a member of the set stored at key.
+// SISMEMBER key member
+// https://redis.io/commands/sismember
+type SIsMember struct {
+	baseCmd
+	key    string
+	member []byte
+}
+
+func parseSIsMember(b baseCmd) (*SIsMember, error) {
+	cmd := &SIsMember{baseCmd: b}
+	if len(cmd.args) != 2 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+	cmd.member = cmd.args[1]
+	return cmd, nil
+}
+
+func (cmd *SIsMember) Run(w Writer, red Redka) (any, error) {
+	ok, err := red.Set().IsMember(cmd.key, cmd.member)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	if ok {
+		w.WriteInt(1)
+	} else {
+		w.WriteInt(0)
+	}
+	return ok, nil
+}
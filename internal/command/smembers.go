@@ -0,0 +1,31 @@
+package command
+
+// Returns all members of the set stored at key.
+// SMEMBERS key
+// https://redis.io/commands/smembers
+type SMembers struct {
+	baseCmd
+	key string
+}
+
+func parseSMembers(b baseCmd) (*SMembers, error) {
+	cmd := &SMembers{baseCmd: b}
+	if len(cmd.args) != 1 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+	return cmd, nil
+}
+
+func (cmd *SMembers) Run(w Writer, red Redka) (any, error) {
+	elems, err := red.Set().Members(cmd.key)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteArray(len(elems))
+	for _, elem := range elems {
+		w.WriteBulk(elem.Bytes())
+	}
+	return elems, nil
+}
@@ -0,0 +1,34 @@
+package command
+
+import "github.com/nalgeon/redka/internal/core"
+
+// Removes and returns a random member from the set stored at key.
+// SPOP key
+// https://redis.io/commands/spop
+type SPop struct {
+	baseCmd
+	key string
+}
+
+func parseSPop(b baseCmd) (*SPop, error) {
+	cmd := &SPop{baseCmd: b}
+	if len(cmd.args) != 1 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+	return cmd, nil
+}
+
+func (cmd *SPop) Run(w Writer, red Redka) (any, error) {
+	elem, err := red.Set().Pop(cmd.key)
+	if err == core.ErrNotFound {
+		w.WriteNil()
+		return nil, nil
+	}
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteBulk(elem.Bytes())
+	return elem, nil
+}
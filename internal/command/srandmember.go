@@ -0,0 +1,41 @@
+package command
+
+import "strconv"
+
+// Returns one or more random members from the set stored at key.
+// SRANDMEMBER key [count]
+// https://redis.io/commands/srandmember
+type SRandMember struct {
+	baseCmd
+	key   string
+	count int
+}
+
+func parseSRandMember(b baseCmd) (*SRandMember, error) {
+	cmd := &SRandMember{baseCmd: b, count: 1}
+	if len(cmd.args) != 1 && len(cmd.args) != 2 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+	if len(cmd.args) == 2 {
+		count, err := strconv.Atoi(string(cmd.args[1]))
+		if err != nil {
+			return cmd, ErrInvalidInt
+		}
+		cmd.count = count
+	}
+	return cmd, nil
+}
+
+func (cmd *SRandMember) Run(w Writer, red Redka) (any, error) {
+	elems, err := red.Set().RandMember(cmd.key, cmd.count)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteArray(len(elems))
+	for _, elem := range elems {
+		w.WriteBulk(elem.Bytes())
+	}
+	return elems, nil
+}
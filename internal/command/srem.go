@@ -0,0 +1,33 @@
+package command
+
+// Removes the specified members from the set stored at key.
+// SREM key member [member ...]
+// https://redis.io/commands/srem
+type SRem struct {
+	baseCmd
+	key     string
+	members []any
+}
+
+func parseSRem(b baseCmd) (*SRem, error) {
+	cmd := &SRem{baseCmd: b}
+	if len(cmd.args) < 2 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+	cmd.members = make([]any, len(cmd.args)-1)
+	for i, m := range cmd.args[1:] {
+		cmd.members[i] = m
+	}
+	return cmd, nil
+}
+
+func (cmd *SRem) Run(w Writer, red Redka) (any, error) {
+	n, err := red.Set().Remove(cmd.key, cmd.members...)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteInt(n)
+	return n, nil
+}
@@ -0,0 +1,63 @@
+package command
+
+import "strconv"
+
+// Incrementally iterates over the members of the set stored at key.
+// SSCAN key cursor [MATCH pattern] [COUNT count]
+// https://redis.io/commands/sscan
+type SScan struct {
+	baseCmd
+	key     string
+	cursor  int
+	pattern string
+	count   int
+}
+
+func parseSScan(b baseCmd) (*SScan, error) {
+	cmd := &SScan{baseCmd: b, count: 10}
+	if len(cmd.args) < 2 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.key = string(cmd.args[0])
+
+	cursor, err := strconv.Atoi(string(cmd.args[1]))
+	if err != nil {
+		return cmd, ErrInvalidInt
+	}
+	cmd.cursor = cursor
+
+	rest := cmd.args[2:]
+	for len(rest) > 0 {
+		switch {
+		case equalFold(rest[0], "match") && len(rest) >= 2:
+			cmd.pattern = string(rest[1])
+			rest = rest[2:]
+		case equalFold(rest[0], "count") && len(rest) >= 2:
+			count, err := strconv.Atoi(string(rest[1]))
+			if err != nil {
+				return cmd, ErrInvalidInt
+			}
+			cmd.count = count
+			rest = rest[2:]
+		default:
+			return cmd, ErrInvalidArgNum
+		}
+	}
+
+	return cmd, nil
+}
+
+func (cmd *SScan) Run(w Writer, red Redka) (any, error) {
+	res, err := red.Set().Scan(cmd.key, cmd.cursor, cmd.pattern, cmd.count)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteArray(2)
+	w.WriteBulkString(strconv.Itoa(res.Cursor))
+	w.WriteArray(len(res.Elems))
+	for _, elem := range res.Elems {
+		w.WriteBulk(elem.Bytes())
+	}
+	return res, nil
+}
@@ -0,0 +1,33 @@
+package command
+
+// Stores the union of the sets stored at the given keys into destkey.
+// SUNIONSTORE destkey key [key ...]
+// https://redis.io/commands/sunionstore
+type SUnionStore struct {
+	baseCmd
+	destKey string
+	srcKeys []string
+}
+
+func parseSUnionStore(b baseCmd) (*SUnionStore, error) {
+	cmd := &SUnionStore{baseCmd: b}
+	if len(cmd.args) < 2 {
+		return cmd, ErrInvalidArgNum
+	}
+	cmd.destKey = string(cmd.args[0])
+	cmd.srcKeys = make([]string, len(cmd.args)-1)
+	for i, k := range cmd.args[1:] {
+		cmd.srcKeys[i] = string(k)
+	}
+	return cmd, nil
+}
+
+func (cmd *SUnionStore) Run(w Writer, red Redka) (any, error) {
+	n, err := red.Set().UnionStore(cmd.destKey, cmd.srcKeys...)
+	if err != nil {
+		w.WriteError(cmd.Error(err))
+		return nil, err
+	}
+	w.WriteInt(n)
+	return n, nil
+}
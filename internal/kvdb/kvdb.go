@@ -0,0 +1,65 @@
+// Package kvdb abstracts the storage layer used by the data type
+// repositories (rstring, rhash, rkey, rset, ...). Most repositories
+// still query their own SQL schema directly through SQLCapable, but
+// Backend also exposes a generic, non-SQL Update/View/Get/Set/Delete/
+// Scan surface (KVCapable) for backends that don't speak SQL at all,
+// such as the in-memory one in memory.go.
+//
+// rstring/rset/rkey are not rewired onto KVCapable yet — that's a
+// larger, separate change touching every repository's schema-bound
+// query code, not just this package — so Open's memory:// backend is
+// usable standalone (and by future repositories written against
+// KVCapable) but does not yet back the existing SQL-only ones. A
+// Pebble-backed implementation is deferred for the same reason plus a
+// new vendored dependency; Open rejects pebble:// until one exists.
+package kvdb
+
+import "errors"
+
+// ErrNotSQL is returned when SQL-specific functionality (schema
+// migrations, raw row access) is requested from a backend that does
+// not support it.
+var ErrNotSQL = errors.New("backend does not support SQL access")
+
+// ErrReadOnly is returned when a KVCapable Tx opened via View attempts
+// a write (Set or Delete).
+var ErrReadOnly = errors.New("kvdb: write inside a read-only transaction")
+
+// Backend is the storage connection underlying a repository.
+type Backend interface {
+	// Close releases any resources held by the backend.
+	Close() error
+}
+
+// SQLCapable is implemented by backends that expose their underlying
+// *sql.DB, so repositories can run their schema's queries directly.
+// Callers should type-assert and fall back to ErrNotSQL if a Backend
+// does not implement it.
+type SQLCapable interface {
+	// SQL returns the backend's underlying database handle as `any`
+	// (concretely *sql.DB) so SQL-specific code can use it directly.
+	SQL() any
+}
+
+// KVCapable is implemented by backends that support the generic
+// transactional key-value API (Tx), as an alternative to SQLCapable
+// for backends with no SQL engine underneath.
+type KVCapable interface {
+	// Update runs fn in a read-write transaction.
+	Update(fn func(tx Tx) error) error
+	// View runs fn in a read-only transaction; Set and Delete
+	// within it return ErrReadOnly.
+	View(fn func(tx Tx) error) error
+}
+
+// Tx is a transaction against a KVCapable backend.
+type Tx interface {
+	// Get returns the value stored at key. ok is false if key is unset.
+	Get(key string) (value []byte, ok bool, err error)
+	// Set stores value at key, overwriting any existing value.
+	Set(key string, value []byte) error
+	// Delete removes key, if it exists.
+	Delete(key string) error
+	// Scan returns every key-value pair whose key starts with prefix.
+	Scan(prefix string) (map[string][]byte, error)
+}
@@ -0,0 +1,77 @@
+package kvdb
+
+import (
+	"strings"
+	"sync"
+)
+
+// memoryBackend is a process-local, in-memory KVCapable Backend. It
+// keeps no schema and no durability: closing it (or the process
+// exiting) discards everything.
+type memoryBackend struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+// NewMemory creates an empty in-memory Backend.
+func NewMemory() Backend {
+	return &memoryBackend{data: make(map[string][]byte)}
+}
+
+func (b *memoryBackend) Close() error {
+	return nil
+}
+
+// Update runs fn in a read-write transaction. memoryBackend has no
+// row-level locking, so the whole backend is held for fn's duration,
+// same as SQLite's writer serialization. Writes are applied to the
+// backend as fn makes them and are not undone if fn returns an error.
+func (b *memoryBackend) Update(fn func(tx Tx) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return fn(&memoryTx{backend: b})
+}
+
+// View runs fn in a read-only transaction.
+func (b *memoryBackend) View(fn func(tx Tx) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return fn(&memoryTx{backend: b, readOnly: true})
+}
+
+// memoryTx is the Tx implementation behind memoryBackend.Update/View.
+type memoryTx struct {
+	backend  *memoryBackend
+	readOnly bool
+}
+
+func (tx *memoryTx) Get(key string) ([]byte, bool, error) {
+	val, ok := tx.backend.data[key]
+	return val, ok, nil
+}
+
+func (tx *memoryTx) Set(key string, value []byte) error {
+	if tx.readOnly {
+		return ErrReadOnly
+	}
+	tx.backend.data[key] = value
+	return nil
+}
+
+func (tx *memoryTx) Delete(key string) error {
+	if tx.readOnly {
+		return ErrReadOnly
+	}
+	delete(tx.backend.data, key)
+	return nil
+}
+
+func (tx *memoryTx) Scan(prefix string) (map[string][]byte, error) {
+	out := make(map[string][]byte)
+	for key, val := range tx.backend.data {
+		if strings.HasPrefix(key, prefix) {
+			out[key] = val
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,53 @@
+package kvdb
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Open opens a Backend from a DSN, dispatching on its scheme:
+//
+//	sqlite:///path/to/redka.db  - the data type repositories' native
+//	                              backend; they query SQLite's schema
+//	                              directly through SQLCapable.
+//	memory://                   - a process-local, non-durable
+//	                              KVCapable backend (see memory.go).
+//	                              Not yet usable by rstring/rset/rkey,
+//	                              which are SQL-only for now; the path
+//	                              after "://" is ignored, since there
+//	                              is no registry of named in-memory
+//	                              stores to share across Opens.
+//
+// Any other scheme, including pebble:// (no Pebble-backed Backend
+// exists yet), is rejected rather than accepted and left non-functional.
+func Open(dsn string) (Backend, error) {
+	scheme, rest, ok := strings.Cut(dsn, "://")
+	if !ok {
+		return nil, fmt.Errorf("kvdb: invalid dsn %q, expected scheme://path", dsn)
+	}
+
+	switch scheme {
+	case "sqlite":
+		db, err := sql.Open("sqlite3", rest)
+		if err != nil {
+			return nil, err
+		}
+		return NewSQLite(db), nil
+	case "memory":
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("kvdb: unsupported backend scheme %q, only sqlite:// and memory:// are supported", scheme)
+	}
+}
+
+// GetTestBackend returns a fresh in-memory SQLite Backend for use in
+// tests, along with a cleanup func to release it.
+func GetTestBackend() (backend Backend, cleanup func()) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+	b := NewSQLite(db)
+	return b, func() { _ = b.Close() }
+}
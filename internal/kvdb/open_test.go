@@ -0,0 +1,109 @@
+package kvdb
+
+import "testing"
+
+func TestOpenRejectsUnsupportedSchemes(t *testing.T) {
+	tests := []string{"pebble:///tmp/x", "badger://"}
+	for _, dsn := range tests {
+		if _, err := Open(dsn); err == nil {
+			t.Errorf("Open(%q) succeeded, want an error (only sqlite:// and memory:// are supported)", dsn)
+		}
+	}
+}
+
+func TestOpenSQLite(t *testing.T) {
+	backend, err := Open("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer backend.Close()
+
+	if _, ok := backend.(SQLCapable); !ok {
+		t.Fatal("sqlite backend does not implement SQLCapable")
+	}
+}
+
+func TestOpenMemory(t *testing.T) {
+	backend, err := Open("memory://")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer backend.Close()
+
+	if _, ok := backend.(KVCapable); !ok {
+		t.Fatal("memory backend does not implement KVCapable")
+	}
+}
+
+func TestMemoryBackendGetSetDelete(t *testing.T) {
+	backend, err := Open("memory://")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer backend.Close()
+	kv := backend.(KVCapable)
+
+	err = kv.Update(func(tx Tx) error {
+		return tx.Set("k", []byte("v"))
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	var val []byte
+	var ok bool
+	err = kv.View(func(tx Tx) error {
+		var err error
+		val, ok, err = tx.Get("k")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("View: %v", err)
+	}
+	if !ok || string(val) != "v" {
+		t.Fatalf("Get(k) = (%q, %v), want (\"v\", true)", val, ok)
+	}
+
+	err = kv.Update(func(tx Tx) error {
+		return tx.Delete("k")
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	err = kv.View(func(tx Tx) error {
+		var err error
+		_, ok, err = tx.Get("k")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("View: %v", err)
+	}
+	if ok {
+		t.Fatal("Get(k) after Delete still reports ok=true")
+	}
+}
+
+func TestMemoryBackendViewRejectsWrites(t *testing.T) {
+	backend, err := Open("memory://")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer backend.Close()
+	kv := backend.(KVCapable)
+
+	err = kv.View(func(tx Tx) error {
+		return tx.Set("k", []byte("v"))
+	})
+	if err != ErrReadOnly {
+		t.Fatalf("Set inside View error = %v, want %v", err, ErrReadOnly)
+	}
+}
+
+func TestGetTestBackendIsSQLCapable(t *testing.T) {
+	backend, cleanup := GetTestBackend()
+	defer cleanup()
+
+	if _, ok := backend.(SQLCapable); !ok {
+		t.Fatal("GetTestBackend() backend does not implement SQLCapable")
+	}
+}
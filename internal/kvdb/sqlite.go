@@ -0,0 +1,24 @@
+package kvdb
+
+import "database/sql"
+
+// sqliteBackend wraps the CGO-free SQLite connection used by the data
+// type repositories. It implements Backend and SQLCapable so they can
+// reach the underlying *sql.DB and run their own schema's queries
+// directly, rather than through a generic key-value Tx.
+type sqliteBackend struct {
+	db *sql.DB
+}
+
+// NewSQLite wraps an existing SQLite connection as a Backend.
+func NewSQLite(db *sql.DB) Backend {
+	return &sqliteBackend{db: db}
+}
+
+func (b *sqliteBackend) Close() error {
+	return b.db.Close()
+}
+
+func (b *sqliteBackend) SQL() any {
+	return b.db
+}
@@ -0,0 +1,100 @@
+// Package rlimit implements a GCRA (Generic Cell Rate Algorithm) rate
+// limiter on top of the string repository. It is redka's equivalent of
+// the throttled/redis-cell CL.THROTTLE pattern, but needs no WATCH/MULTI:
+// each call to Limit reads and writes the limiter state inside a single
+// rstring transaction, which SQLite already serializes.
+package rlimit
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/nalgeon/redka/internal/core"
+	"github.com/nalgeon/redka/internal/rstring"
+)
+
+// ErrInvalidLimit is returned by Limit when countPerPeriod or period
+// is not positive, or when countPerPeriod is so much larger than period
+// that the emission interval (period / countPerPeriod) rounds down to
+// zero, since that interval is later divided into.
+var ErrInvalidLimit = errors.New("rlimit: countPerPeriod and period must be positive, and period must be at least countPerPeriod nanoseconds")
+
+// DB is a GCRA rate limiter backed by the string repository.
+// Each limited key stores a single value: the theoretical arrival time
+// (TAT) of the next allowed cell, as a Unix nanosecond timestamp.
+type DB struct {
+	str *rstring.DB
+}
+
+// New creates a rate limiter that stores its state in str.
+func New(str *rstring.DB) *DB {
+	return &DB{str: str}
+}
+
+// Limit applies the GCRA algorithm to key, allowing up to countPerPeriod
+// operations per period with a burst of up to maxBurst, and consuming
+// quantity units of the limit in this call.
+//
+// Returns limited=true if the call should be rejected. remaining is the
+// number of operations left in the current burst. retryAfter is how long
+// the caller should wait before retrying (zero when not limited).
+// resetAfter is how long until the limit fully resets to maxBurst.
+//
+// If the key holds a value that was not written by Limit, returns
+// core.ErrValueType. If countPerPeriod or period is not positive, or the
+// emission interval they imply rounds down to zero, returns
+// ErrInvalidLimit.
+func (d *DB) Limit(key string, maxBurst int, countPerPeriod int, period time.Duration, quantity int) (limited bool, remaining int, retryAfter, resetAfter time.Duration, err error) {
+	if countPerPeriod <= 0 || period <= 0 {
+		return false, 0, 0, 0, ErrInvalidLimit
+	}
+
+	now := time.Now()
+	emissionInterval := period / time.Duration(countPerPeriod)
+	if emissionInterval <= 0 {
+		return false, 0, 0, 0, ErrInvalidLimit
+	}
+	increment := emissionInterval * time.Duration(quantity)
+	// maxInterval is the tolerance above the emission interval that
+	// lets a burst of up to maxBurst requests through: maxBurst extra
+	// emission intervals, not a full extra period (which would let the
+	// effective burst size grow to countPerPeriod+maxBurst).
+	maxInterval := emissionInterval * time.Duration(maxBurst)
+
+	err = d.str.Update(func(tx *rstring.Tx) error {
+		tat := now
+		val, getErr := tx.Get(key)
+		if getErr != nil && getErr != core.ErrNotFound {
+			return getErr
+		}
+		if getErr == nil {
+			ns, parseErr := strconv.ParseInt(val.String(), 10, 64)
+			if parseErr != nil {
+				return core.ErrValueType
+			}
+			tat = time.Unix(0, ns)
+		}
+		if tat.Before(now) {
+			tat = now
+		}
+
+		newTAT := tat.Add(increment)
+		allowAt := newTAT.Sub(now)
+
+		if allowAt > maxInterval {
+			limited = true
+			retryAfter = allowAt - maxInterval
+			resetAfter = tat.Sub(now)
+			remaining = 0
+			return nil
+		}
+
+		remaining = int((maxInterval - allowAt) / emissionInterval)
+		resetAfter = newTAT.Sub(now)
+		ttl := period + maxInterval
+		return tx.SetExpires(key, strconv.FormatInt(newTAT.UnixNano(), 10), ttl)
+	})
+
+	return limited, remaining, retryAfter, resetAfter, err
+}
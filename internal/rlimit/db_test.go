@@ -0,0 +1,84 @@
+package rlimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nalgeon/redka/internal/kvdb"
+	"github.com/nalgeon/redka/internal/rstring"
+)
+
+// TestLimitRejectsNonPositiveInputs guards against the integer
+// divide-by-zero panic that countPerPeriod<=0 or period<=0 used to
+// cause when computing the emission interval, and against the same
+// panic when countPerPeriod and period are both positive but the
+// emission interval they imply (period/countPerPeriod) rounds down to
+// zero. The guard runs before the rate limiter ever touches its
+// backend, so a nil *DB is safe here.
+func TestLimitRejectsNonPositiveInputs(t *testing.T) {
+	var d *DB
+	tests := []struct {
+		name           string
+		countPerPeriod int
+		period         time.Duration
+	}{
+		{"zero countPerPeriod", 0, time.Minute},
+		{"negative countPerPeriod", -1, time.Minute},
+		{"zero period", 10, 0},
+		{"negative period", 10, -time.Second},
+		{"countPerPeriod exceeds period in nanoseconds", 2_000_000_000, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, _, err := d.Limit("key", 5, tt.countPerPeriod, tt.period, 1)
+			if err != ErrInvalidLimit {
+				t.Fatalf("Limit(countPerPeriod=%d, period=%s) error = %v, want %v",
+					tt.countPerPeriod, tt.period, err, ErrInvalidLimit)
+			}
+		})
+	}
+}
+
+// TestLimitAllowsBurstThenRejectsAndRecovers exercises the actual GCRA
+// algorithm against a real rstring.DB: the first call within the
+// burst is allowed, the next one is rejected, and the call is allowed
+// again once retryAfter has elapsed.
+func TestLimitAllowsBurstThenRejectsAndRecovers(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	str := rstring.New(backend)
+	d := New(str)
+
+	const period = 200 * time.Millisecond
+	limited, remaining, _, _, err := d.Limit("key", 1, 1, period, 1)
+	if err != nil {
+		t.Fatalf("Limit: %v", err)
+	}
+	if limited {
+		t.Fatal("first call within the burst was limited, want allowed")
+	}
+	if remaining != 0 {
+		t.Errorf("remaining after the burst is exhausted = %d, want 0", remaining)
+	}
+
+	limited, _, retryAfter, _, err := d.Limit("key", 1, 1, period, 1)
+	if err != nil {
+		t.Fatalf("Limit: %v", err)
+	}
+	if !limited {
+		t.Fatal("call beyond the burst was allowed, want limited")
+	}
+	if retryAfter <= 0 {
+		t.Fatalf("retryAfter = %s, want > 0", retryAfter)
+	}
+
+	time.Sleep(retryAfter + 50*time.Millisecond)
+
+	limited, _, _, _, err = d.Limit("key", 1, 1, period, 1)
+	if err != nil {
+		t.Fatalf("Limit: %v", err)
+	}
+	if limited {
+		t.Fatal("call after retryAfter elapsed was still limited, want allowed")
+	}
+}
@@ -0,0 +1,166 @@
+// Package rset is a database-backed set repository.
+// It provides methods to interact with sets in the database.
+package rset
+
+import (
+	"github.com/nalgeon/redka/internal/core"
+	"github.com/nalgeon/redka/internal/kvdb"
+	"github.com/nalgeon/redka/internal/sqlx"
+)
+
+// DB is a database-backed set repository.
+// A set is an unordered collection of unique strings (elements)
+// associated with a key. Use the set repository to work with sets.
+type DB struct {
+	*sqlx.DB[*Tx]
+}
+
+// New connects to the set repository using the given storage backend.
+// Does not create the database schema.
+func New(backend kvdb.Backend) *DB {
+	d := sqlx.New(backend, NewTx)
+	return &DB{d}
+}
+
+// Add adds the given elements to the set stored at key.
+// Creates the key if it does not exist yet.
+// Returns the number of elements added (ignoring ones already present).
+// If the key exists but is not a set, returns ErrKeyType.
+func (d *DB) Add(key string, elems ...any) (int, error) {
+	var n int
+	err := d.Update(func(tx *Tx) error {
+		var err error
+		n, err = tx.Add(key, elems...)
+		return err
+	})
+	return n, err
+}
+
+// Remove removes the given elements from the set stored at key.
+// Returns the number of elements actually removed.
+// If the key does not exist, returns 0.
+// If the key exists but is not a set, returns ErrKeyType.
+func (d *DB) Remove(key string, elems ...any) (int, error) {
+	var n int
+	err := d.Update(func(tx *Tx) error {
+		var err error
+		n, err = tx.Remove(key, elems...)
+		return err
+	})
+	return n, err
+}
+
+// Members returns all elements of the set stored at key.
+// If the key does not exist, returns an empty slice.
+// If the key exists but is not a set, returns ErrKeyType.
+func (d *DB) Members(key string) ([]core.Value, error) {
+	tx := NewTx(d.SQL)
+	return tx.Members(key)
+}
+
+// IsMember reports whether elem is a member of the set stored at key.
+// If the key does not exist, returns false.
+// If the key exists but is not a set, returns ErrKeyType.
+func (d *DB) IsMember(key string, elem any) (bool, error) {
+	tx := NewTx(d.SQL)
+	return tx.IsMember(key, elem)
+}
+
+// Card returns the number of elements in the set stored at key.
+// If the key does not exist, returns 0.
+// If the key exists but is not a set, returns ErrKeyType.
+func (d *DB) Card(key string) (int, error) {
+	tx := NewTx(d.SQL)
+	return tx.Card(key)
+}
+
+// Pop removes and returns a random element from the set stored at key.
+// If the key does not exist, returns core.ErrNotFound.
+// If the key exists but is not a set, returns ErrKeyType.
+func (d *DB) Pop(key string) (core.Value, error) {
+	var val core.Value
+	err := d.Update(func(tx *Tx) error {
+		var err error
+		val, err = tx.Pop(key)
+		return err
+	})
+	return val, err
+}
+
+// RandMember returns count distinct random elements from the set stored
+// at key. If count is negative, elements may repeat and abs(count)
+// elements are returned. If the key does not exist, returns an empty slice.
+// If the key exists but is not a set, returns ErrKeyType.
+func (d *DB) RandMember(key string, count int) ([]core.Value, error) {
+	tx := NewTx(d.SQL)
+	return tx.RandMember(key, count)
+}
+
+// Union returns the members of the union of the sets stored at keys.
+// Keys that do not exist are treated as empty sets.
+func (d *DB) Union(keys ...string) ([]core.Value, error) {
+	tx := NewTx(d.SQL)
+	return tx.Union(keys...)
+}
+
+// Inter returns the members of the intersection of the sets stored at keys.
+// Keys that do not exist are treated as empty sets (making the result empty).
+func (d *DB) Inter(keys ...string) ([]core.Value, error) {
+	tx := NewTx(d.SQL)
+	return tx.Inter(keys...)
+}
+
+// Diff returns the members of the set stored at keys[0] that are not
+// present in any of the other sets. Keys that do not exist are treated
+// as empty sets.
+func (d *DB) Diff(keys ...string) ([]core.Value, error) {
+	tx := NewTx(d.SQL)
+	return tx.Diff(keys...)
+}
+
+// UnionStore stores the union of the sets stored at srcKeys into destKey,
+// overwriting destKey if it already exists. Returns the size of the result.
+func (d *DB) UnionStore(destKey string, srcKeys ...string) (int, error) {
+	var n int
+	err := d.Update(func(tx *Tx) error {
+		var err error
+		n, err = tx.UnionStore(destKey, srcKeys...)
+		return err
+	})
+	return n, err
+}
+
+// InterStore stores the intersection of the sets stored at srcKeys into
+// destKey, overwriting destKey if it already exists. Returns the size
+// of the result.
+func (d *DB) InterStore(destKey string, srcKeys ...string) (int, error) {
+	var n int
+	err := d.Update(func(tx *Tx) error {
+		var err error
+		n, err = tx.InterStore(destKey, srcKeys...)
+		return err
+	})
+	return n, err
+}
+
+// DiffStore stores the difference between the set stored at srcKeys[0]
+// and the rest of srcKeys into destKey, overwriting destKey if it already
+// exists. Returns the size of the result.
+func (d *DB) DiffStore(destKey string, srcKeys ...string) (int, error) {
+	var n int
+	err := d.Update(func(tx *Tx) error {
+		var err error
+		n, err = tx.DiffStore(destKey, srcKeys...)
+		return err
+	})
+	return n, err
+}
+
+// Scan iterates over the elements of the set stored at key, starting
+// from cursor, matching elements against pattern (if not empty) and
+// returning up to count elements per call. Returns the cursor to resume
+// from, or 0 when the scan is complete.
+func (d *DB) Scan(key string, cursor int, pattern string, count int) (ScanResult, error) {
+	tx := NewTx(d.SQL)
+	return tx.Scan(key, cursor, pattern, count)
+}
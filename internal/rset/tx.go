@@ -0,0 +1,420 @@
+package rset
+
+import (
+	"math/rand"
+	"path"
+	"sort"
+
+	"github.com/nalgeon/redka/internal/core"
+	"github.com/nalgeon/redka/internal/sqlx"
+)
+
+// ScanResult is a page of elements returned by Scan,
+// along with the cursor to continue scanning from.
+type ScanResult struct {
+	Cursor int
+	Elems  []core.Value
+}
+
+// Tx is a set repository transaction.
+type Tx struct {
+	tx sqlx.Tx
+}
+
+// NewTx creates a set repository transaction
+// from an existing database transaction or connection.
+func NewTx(tx sqlx.Tx) *Tx {
+	return &Tx{tx: tx}
+}
+
+// Add is the transactional implementation behind DB.Add.
+func (tx *Tx) Add(key string, elems ...any) (int, error) {
+	keyID, err := sqlx.GetOrCreateKey(tx.tx, key, core.TypeSet)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, elem := range elems {
+		val := core.Value(sqlx.ToBytes(elem))
+		res, err := tx.tx.Exec(sqlSetAdd, keyID, val)
+		if err != nil {
+			return n, err
+		}
+		if res.RowsAffected() > 0 {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Remove is the transactional implementation behind DB.Remove.
+// Removing from a key that does not exist is a no-op.
+func (tx *Tx) Remove(key string, elems ...any) (int, error) {
+	keyID, err := sqlx.GetKeyID(tx.tx, key, core.TypeSet)
+	if err != nil {
+		if err == core.ErrNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var n int
+	for _, elem := range elems {
+		val := core.Value(sqlx.ToBytes(elem))
+		res, err := tx.tx.Exec(sqlSetRemove, keyID, val)
+		if err != nil {
+			return n, err
+		}
+		if res.RowsAffected() > 0 {
+			n++
+		}
+	}
+	if n > 0 {
+		if err := tx.deleteIfEmpty(key, keyID); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Members is the transactional implementation behind DB.Members.
+func (tx *Tx) Members(key string) ([]core.Value, error) {
+	keyID, err := sqlx.GetKeyID(tx.tx, key, core.TypeSet)
+	if err != nil {
+		if err == core.ErrNotFound {
+			return []core.Value{}, nil
+		}
+		return nil, err
+	}
+	return tx.scanAll(keyID)
+}
+
+// IsMember is the transactional implementation behind DB.IsMember.
+func (tx *Tx) IsMember(key string, elem any) (bool, error) {
+	keyID, err := sqlx.GetKeyID(tx.tx, key, core.TypeSet)
+	if err != nil {
+		if err == core.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	val := core.Value(sqlx.ToBytes(elem))
+	var n int
+	err = tx.tx.QueryRow(sqlSetIsMember, keyID, val).Scan(&n)
+	return n > 0, err
+}
+
+// Card is the transactional implementation behind DB.Card.
+func (tx *Tx) Card(key string) (int, error) {
+	keyID, err := sqlx.GetKeyID(tx.tx, key, core.TypeSet)
+	if err != nil {
+		if err == core.ErrNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	var n int
+	err = tx.tx.QueryRow(sqlSetCard, keyID).Scan(&n)
+	return n, err
+}
+
+// Pop is the transactional implementation behind DB.Pop.
+func (tx *Tx) Pop(key string) (core.Value, error) {
+	keyID, err := sqlx.GetKeyID(tx.tx, key, core.TypeSet)
+	if err != nil {
+		return nil, err
+	}
+	elems, err := tx.scanAll(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(elems) == 0 {
+		return nil, core.ErrNotFound
+	}
+	elem := elems[rand.Intn(len(elems))]
+	if _, err := tx.tx.Exec(sqlSetRemove, keyID, elem); err != nil {
+		return nil, err
+	}
+	if err := tx.deleteIfEmpty(key, keyID); err != nil {
+		return nil, err
+	}
+	return elem, nil
+}
+
+// RandMember is the transactional implementation behind DB.RandMember.
+func (tx *Tx) RandMember(key string, count int) ([]core.Value, error) {
+	keyID, err := sqlx.GetKeyID(tx.tx, key, core.TypeSet)
+	if err != nil {
+		if err == core.ErrNotFound {
+			return []core.Value{}, nil
+		}
+		return nil, err
+	}
+	elems, err := tx.scanAll(keyID)
+	if err != nil || len(elems) == 0 {
+		return []core.Value{}, err
+	}
+
+	if count < 0 {
+		n := -count
+		out := make([]core.Value, n)
+		for i := range out {
+			out[i] = elems[rand.Intn(len(elems))]
+		}
+		return out, nil
+	}
+
+	rand.Shuffle(len(elems), func(i, j int) { elems[i], elems[j] = elems[j], elems[i] })
+	if count > len(elems) {
+		count = len(elems)
+	}
+	return elems[:count], nil
+}
+
+// Union is the transactional implementation behind DB.Union.
+func (tx *Tx) Union(keys ...string) ([]core.Value, error) {
+	sets, err := tx.loadSets(keys)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]core.Value{}
+	for _, set := range sets {
+		for _, v := range set {
+			out[string(v)] = v
+		}
+	}
+	return mapValues(out), nil
+}
+
+// Inter is the transactional implementation behind DB.Inter.
+func (tx *Tx) Inter(keys ...string) ([]core.Value, error) {
+	sets, err := tx.loadSets(keys)
+	if err != nil {
+		return nil, err
+	}
+	if len(sets) == 0 {
+		return []core.Value{}, nil
+	}
+	counts := map[string]int{}
+	vals := map[string]core.Value{}
+	for _, set := range sets {
+		seen := map[string]bool{}
+		for _, v := range set {
+			k := string(v)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			counts[k]++
+			vals[k] = v
+		}
+	}
+	out := map[string]core.Value{}
+	for k, n := range counts {
+		if n == len(sets) {
+			out[k] = vals[k]
+		}
+	}
+	return mapValues(out), nil
+}
+
+// Diff is the transactional implementation behind DB.Diff.
+func (tx *Tx) Diff(keys ...string) ([]core.Value, error) {
+	sets, err := tx.loadSets(keys)
+	if err != nil || len(sets) == 0 {
+		return []core.Value{}, err
+	}
+	exclude := map[string]bool{}
+	for _, set := range sets[1:] {
+		for _, v := range set {
+			exclude[string(v)] = true
+		}
+	}
+	out := map[string]core.Value{}
+	for _, v := range sets[0] {
+		if !exclude[string(v)] {
+			out[string(v)] = v
+		}
+	}
+	return mapValues(out), nil
+}
+
+// UnionStore is the transactional implementation behind DB.UnionStore.
+func (tx *Tx) UnionStore(destKey string, srcKeys ...string) (int, error) {
+	elems, err := tx.Union(srcKeys...)
+	if err != nil {
+		return 0, err
+	}
+	return tx.storeResult(destKey, elems)
+}
+
+// InterStore is the transactional implementation behind DB.InterStore.
+func (tx *Tx) InterStore(destKey string, srcKeys ...string) (int, error) {
+	elems, err := tx.Inter(srcKeys...)
+	if err != nil {
+		return 0, err
+	}
+	return tx.storeResult(destKey, elems)
+}
+
+// DiffStore is the transactional implementation behind DB.DiffStore.
+func (tx *Tx) DiffStore(destKey string, srcKeys ...string) (int, error) {
+	elems, err := tx.Diff(srcKeys...)
+	if err != nil {
+		return 0, err
+	}
+	return tx.storeResult(destKey, elems)
+}
+
+// Scan is the transactional implementation behind DB.Scan.
+func (tx *Tx) Scan(key string, cursor int, pattern string, count int) (ScanResult, error) {
+	keyID, err := sqlx.GetKeyID(tx.tx, key, core.TypeSet)
+	if err != nil {
+		if err == core.ErrNotFound {
+			return ScanResult{}, nil
+		}
+		return ScanResult{}, err
+	}
+
+	rows, err := tx.tx.Query(sqlSetScan, keyID, cursor, count+1)
+	if err != nil {
+		return ScanResult{}, err
+	}
+	defer rows.Close()
+
+	// Scan up to count+1 raw rows: the extra row, if present, only
+	// serves as a probe to detect that more data remains, and is
+	// never itself matched against pattern or returned.
+	var lastRawRowID int
+	var rawCount int
+	var elems []core.Value
+	for rows.Next() {
+		var rowID int
+		var val core.Value
+		if err := rows.Scan(&rowID, &val); err != nil {
+			return ScanResult{}, err
+		}
+		rawCount++
+		if rawCount > count {
+			break
+		}
+		lastRawRowID = rowID
+		if pattern != "" {
+			if ok, _ := path.Match(pattern, val.String()); !ok {
+				continue
+			}
+		}
+		elems = append(elems, val)
+	}
+
+	// More unfiltered rows remain iff the probe row was fetched, which
+	// is independent of how many rows survived the MATCH filter. The
+	// cursor resumes from the last raw row actually scanned in this
+	// page (not the never-returned probe row), so the next call picks
+	// up where this one left off even if nothing in this page matched.
+	next := 0
+	if rawCount > count {
+		next = lastRawRowID
+	}
+	return ScanResult{Cursor: next, Elems: elems}, rows.Err()
+}
+
+// deleteIfEmpty deletes key's rkey row once it has no set members left,
+// so a set drained by Remove or Pop disappears like real Redis instead
+// of lingering as an existing, zero-member set.
+func (tx *Tx) deleteIfEmpty(key string, keyID int) error {
+	var n int
+	if err := tx.tx.QueryRow(sqlSetCard, keyID).Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+	_, err := tx.tx.Exec(sqlKeyDelete, key)
+	return err
+}
+
+// scanAll returns every element currently stored under keyID.
+func (tx *Tx) scanAll(keyID int) ([]core.Value, error) {
+	rows, err := tx.tx.Query(sqlSetMembers, keyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var elems []core.Value
+	for rows.Next() {
+		var val core.Value
+		if err := rows.Scan(&val); err != nil {
+			return nil, err
+		}
+		elems = append(elems, val)
+	}
+	return elems, rows.Err()
+}
+
+// loadSets resolves keys to their member slices, treating missing
+// or non-existent keys as empty sets.
+func (tx *Tx) loadSets(keys []string) ([][]core.Value, error) {
+	sets := make([][]core.Value, len(keys))
+	for i, key := range keys {
+		keyID, err := sqlx.GetKeyID(tx.tx, key, core.TypeSet)
+		if err != nil {
+			if err == core.ErrNotFound {
+				sets[i] = nil
+				continue
+			}
+			return nil, err
+		}
+		elems, err := tx.scanAll(keyID)
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = elems
+	}
+	return sets, nil
+}
+
+// storeResult overwrites destKey with elems, discarding whatever destKey
+// held before, regardless of its previous type. If elems is empty,
+// destKey is left deleted entirely rather than existing as an empty set.
+func (tx *Tx) storeResult(destKey string, elems []core.Value) (int, error) {
+	if _, err := tx.tx.Exec(sqlSetDelete, destKey); err != nil {
+		return 0, err
+	}
+	if _, err := tx.tx.Exec(sqlKeyDelete, destKey); err != nil {
+		return 0, err
+	}
+	if len(elems) == 0 {
+		return 0, nil
+	}
+	anyElems := make([]any, len(elems))
+	for i, e := range elems {
+		anyElems[i] = e
+	}
+	return tx.Add(destKey, anyElems...)
+}
+
+// mapValues returns the values of m in a stable (sorted) order,
+// so results are deterministic for tests and callers alike.
+func mapValues(m map[string]core.Value) []core.Value {
+	out := make([]core.Value, 0, len(m))
+	for _, v := range m {
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].String() < out[j].String() })
+	return out
+}
+
+// SQL queries run against the rset table, keyed by (key_id, elem).
+const (
+	sqlSetAdd      = `insert into rset (key_id, elem) values (?, ?) on conflict do nothing`
+	sqlSetRemove   = `delete from rset where key_id = ? and elem = ?`
+	sqlSetMembers  = `select elem from rset where key_id = ?`
+	sqlSetIsMember = `select count(*) from rset where key_id = ? and elem = ?`
+	sqlSetCard     = `select count(*) from rset where key_id = ?`
+	sqlSetScan     = `select rowid, elem from rset where key_id = ? and rowid > ? order by rowid limit ?`
+	sqlSetDelete   = `delete from rset where key_id = (select id from rkey where key = ?)`
+	sqlKeyDelete   = `delete from rkey where key = ?`
+)
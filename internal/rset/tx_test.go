@@ -0,0 +1,229 @@
+package rset
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/nalgeon/redka/internal/core"
+	"github.com/nalgeon/redka/internal/kvdb"
+)
+
+func TestScanPaginationCoversAllElements(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	const total = 25
+	elems := make([]any, total)
+	for i := range elems {
+		elems[i] = string(rune('a' + i%26))
+	}
+	if _, err := d.Add("myset", elems...); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	seen := map[string]bool{}
+	cursor := 0
+	for pages := 0; ; pages++ {
+		if pages > total {
+			t.Fatalf("Scan did not terminate after %d pages", pages)
+		}
+		res, err := d.Scan("myset", cursor, "", 10)
+		if err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		for _, v := range res.Elems {
+			seen[v.String()] = true
+		}
+		if res.Cursor == 0 {
+			break
+		}
+		cursor = res.Cursor
+	}
+	if len(seen) != total {
+		t.Fatalf("Scan returned %d distinct elements, want %d (lost elements at page boundaries)", len(seen), total)
+	}
+}
+
+func TestScanPaginationWithMatchKeepsScanning(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	// Only the last few elements match the pattern, so if a page's
+	// filtered count is used to decide "more data remains" instead
+	// of the raw fetched count, the scan stops before reaching them.
+	elems := make([]any, 30)
+	for i := range elems {
+		if i < 27 {
+			elems[i] = "skip-" + string(rune('a'+i%26))
+		} else {
+			elems[i] = "keep-" + string(rune('a'+i%26))
+		}
+	}
+	if _, err := d.Add("myset", elems...); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	var matched []string
+	cursor := 0
+	for pages := 0; ; pages++ {
+		if pages > 30 {
+			t.Fatalf("Scan did not terminate after %d pages", pages)
+		}
+		res, err := d.Scan("myset", cursor, "keep-*", 5)
+		if err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		for _, v := range res.Elems {
+			matched = append(matched, v.String())
+		}
+		if res.Cursor == 0 {
+			break
+		}
+		cursor = res.Cursor
+	}
+	if len(matched) != 3 {
+		t.Fatalf("Scan with MATCH returned %d elements, want 3 (scan stopped early)", len(matched))
+	}
+}
+
+func TestPopMissingKeyReturnsNotFound(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	_, err := d.Pop("missing")
+	if err != core.ErrNotFound {
+		t.Fatalf("Pop on missing key error = %v, want %v", err, core.ErrNotFound)
+	}
+}
+
+func TestRemoveMissingKeyReturnsZero(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	n, err := d.Remove("missing", "x")
+	if err != nil {
+		t.Fatalf("Remove on missing key error = %v, want nil", err)
+	}
+	if n != 0 {
+		t.Fatalf("Remove on missing key = %d, want 0", n)
+	}
+}
+
+func TestRemoveExistingMembers(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	if _, err := d.Add("myset", "a", "b", "c"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	n, err := d.Remove("myset", "a", "b", "z")
+	if err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("Remove = %d, want 2 (z was never a member)", n)
+	}
+}
+
+func TestRemoveDeletesKeyWhenDrained(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	if _, err := d.Add("myset", "a", "b"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := d.Remove("myset", "a", "b"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	sqlDB := backend.(kvdb.SQLCapable).SQL().(*sql.DB)
+	var n int
+	if err := sqlDB.QueryRow(`select count(*) from rkey where key = ?`, "myset").Scan(&n); err != nil {
+		t.Fatalf("counting rkey rows: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("rkey row for myset still exists after Remove drained it, want it deleted")
+	}
+}
+
+func TestPopDeletesKeyWhenDrained(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	if _, err := d.Add("myset", "a"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := d.Pop("myset"); err != nil {
+		t.Fatalf("Pop: %v", err)
+	}
+
+	sqlDB := backend.(kvdb.SQLCapable).SQL().(*sql.DB)
+	var n int
+	if err := sqlDB.QueryRow(`select count(*) from rkey where key = ?`, "myset").Scan(&n); err != nil {
+		t.Fatalf("counting rkey rows: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("rkey row for myset still exists after Pop drained it, want it deleted")
+	}
+}
+
+func TestStoreResultOverwritesDestKeyOfDifferentType(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	sqlDB := backend.(kvdb.SQLCapable).SQL().(*sql.DB)
+	if _, err := sqlDB.Exec(`insert into rkey (key, type) values (?, ?)`, "dest", core.TypeString); err != nil {
+		t.Fatalf("seeding a string rkey row for dest: %v", err)
+	}
+
+	if _, err := d.Add("src", "x"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	n, err := d.UnionStore("dest", "src")
+	if err != nil {
+		t.Fatalf("UnionStore: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("UnionStore = %d, want 1", n)
+	}
+
+	members, err := d.Members("dest")
+	if err != nil {
+		t.Fatalf("Members: %v", err)
+	}
+	if len(members) != 1 || members[0].String() != "x" {
+		t.Fatalf("Members(dest) = %v, want [x]", members)
+	}
+}
+
+func TestStoreResultDeletesDestKeyWhenEmpty(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	if _, err := d.Add("dest", "x"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	// src1 and src2 don't exist, so their intersection is empty.
+	if _, err := d.InterStore("dest", "src1", "src2"); err != nil {
+		t.Fatalf("InterStore: %v", err)
+	}
+
+	sqlDB := backend.(kvdb.SQLCapable).SQL().(*sql.DB)
+	var n int
+	if err := sqlDB.QueryRow(`select count(*) from rkey where key = ?`, "dest").Scan(&n); err != nil {
+		t.Fatalf("counting rkey rows: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("rkey row for dest still exists after InterStore emptied it, want it deleted")
+	}
+}
@@ -0,0 +1,346 @@
+package rstring
+
+import (
+	"math/bits"
+
+	"github.com/nalgeon/redka/internal/core"
+	"github.com/nalgeon/redka/internal/sqlx"
+)
+
+// BitOp is a bitwise operation applied to multiple strings by BitOp.
+type BitOp int
+
+// Bitwise operations supported by BitOp.
+const (
+	BitAnd BitOp = iota
+	BitOr
+	BitXor
+	BitNot
+)
+
+// SetBit sets or clears the bit at offset in the string value stored at key.
+// The string is grown and zero-padded as needed to fit the offset.
+// Returns the original bit value (0 or 1).
+// If the key does not exist, it is treated as an empty string.
+// If the key exists but is not a string, returns ErrKeyType.
+func (d *DB) SetBit(key string, offset uint64, value int) (int, error) {
+	var old int
+	err := d.Update(func(tx *Tx) error {
+		var err error
+		old, err = tx.SetBit(key, offset, value)
+		return err
+	})
+	return old, err
+}
+
+// GetBit returns the bit value at offset in the string value stored at key.
+// Bits beyond the end of the string are considered to be 0.
+// If the key does not exist, returns 0.
+// If the key exists but is not a string, returns ErrKeyType.
+func (d *DB) GetBit(key string, offset uint64) (int, error) {
+	tx := NewTx(d.SQL)
+	return tx.GetBit(key, offset)
+}
+
+// BitCount counts the set bits in the string value stored at key,
+// restricted to the inclusive [start,end] range.
+// If byBit is true, start and end are bit indexes, otherwise byte indexes.
+// Negative indexes count from the end of the string, same as Redis ranges.
+// If the key does not exist, returns 0.
+func (d *DB) BitCount(key string, start, end int64, byBit bool) (int64, error) {
+	tx := NewTx(d.SQL)
+	return tx.BitCount(key, start, end, byBit)
+}
+
+// BitPos returns the position of the first bit set to bit (0 or 1) in the
+// string value stored at key, restricted to the inclusive [start,end] range.
+// If byBit is true, start and end are bit indexes, otherwise byte indexes.
+// hasEnd reports whether the caller gave an explicit end (as opposed to
+// end defaulting to the last bit/byte): when searching for a clear bit
+// (bit == 0) with no explicit end, the string is treated as right-padded
+// with infinite zero bits, so the position right after its content is
+// returned instead of -1 if no clear bit is found within it.
+// Returns -1 if no matching bit is found in the range.
+func (d *DB) BitPos(key string, bit int, start, end int64, byBit, hasEnd bool) (int64, error) {
+	tx := NewTx(d.SQL)
+	return tx.BitPos(key, bit, start, end, byBit, hasEnd)
+}
+
+// BitOp performs a bitwise AND, OR, XOR or NOT between the strings stored
+// at srcKeys and stores the result in destKey. NOT takes exactly one source
+// key. The result is as long as the longest source string; shorter sources
+// (and missing keys) are zero-padded. Returns the length of the result.
+// If destKey exists but is not a string, returns ErrKeyType.
+func (d *DB) BitOp(op BitOp, destKey string, srcKeys ...string) (int64, error) {
+	var n int64
+	err := d.Update(func(tx *Tx) error {
+		var err error
+		n, err = tx.BitOp(op, destKey, srcKeys...)
+		return err
+	})
+	return n, err
+}
+
+// Append appends value to the string value stored at key.
+// If the key does not exist, it is created with value as its value.
+// Returns the length of the string after the append.
+// If the key exists but is not a string, returns ErrKeyType.
+func (d *DB) Append(key string, value []byte) (int, error) {
+	var n int
+	err := d.Update(func(tx *Tx) error {
+		var err error
+		n, err = tx.Append(key, value)
+		return err
+	})
+	return n, err
+}
+
+// SetBit is the transactional implementation behind DB.SetBit.
+func (tx *Tx) SetBit(key string, offset uint64, value int) (int, error) {
+	if value != 0 && value != 1 {
+		return 0, core.ErrValueType
+	}
+	val, err := tx.Get(key)
+	if err != nil && err != core.ErrNotFound {
+		return 0, err
+	}
+	data := val.Bytes()
+	byteIdx := int(offset / 8)
+	if byteIdx >= len(data) {
+		grown := make([]byte, byteIdx+1)
+		copy(grown, data)
+		data = grown
+	}
+	mask := byte(0x80) >> (offset % 8)
+	old := 0
+	if data[byteIdx]&mask != 0 {
+		old = 1
+	}
+	if value == 1 {
+		data[byteIdx] |= mask
+	} else {
+		data[byteIdx] &^= mask
+	}
+	if err := tx.Set(key, data); err != nil {
+		return 0, err
+	}
+	return old, nil
+}
+
+// GetBit is the transactional implementation behind DB.GetBit.
+func (tx *Tx) GetBit(key string, offset uint64) (int, error) {
+	val, err := tx.Get(key)
+	if err == core.ErrNotFound {
+		// Get also reports a key of another type as not found,
+		// so look the key up by type to tell the two apart.
+		if _, keyErr := sqlx.GetKeyID(tx.SQL, key, core.TypeString); keyErr != nil && keyErr != core.ErrNotFound {
+			return 0, keyErr
+		}
+	} else if err != nil {
+		return 0, err
+	}
+	data := val.Bytes()
+	byteIdx := int(offset / 8)
+	if byteIdx >= len(data) {
+		return 0, nil
+	}
+	mask := byte(0x80) >> (offset % 8)
+	if data[byteIdx]&mask != 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// BitCount is the transactional implementation behind DB.BitCount.
+func (tx *Tx) BitCount(key string, start, end int64, byBit bool) (int64, error) {
+	val, err := tx.Get(key)
+	if err != nil && err != core.ErrNotFound {
+		return 0, err
+	}
+	data := val.Bytes()
+	if byBit {
+		s, e, ok := bitRange(len(data)*8, start, end)
+		if !ok {
+			return 0, nil
+		}
+		var count int64
+		for i := s; i <= e; i++ {
+			if bitAt(data, i) == 1 {
+				count++
+			}
+		}
+		return count, nil
+	}
+	s, e, ok := bitRange(len(data), start, end)
+	if !ok {
+		return 0, nil
+	}
+	var count int64
+	for i := s; i <= e; i++ {
+		count += int64(bits.OnesCount8(data[i]))
+	}
+	return count, nil
+}
+
+// BitPos is the transactional implementation behind DB.BitPos.
+func (tx *Tx) BitPos(key string, bit int, start, end int64, byBit, hasEnd bool) (int64, error) {
+	val, err := tx.Get(key)
+	if err != nil && err != core.ErrNotFound {
+		return 0, err
+	}
+	data := val.Bytes()
+	if len(data) == 0 {
+		if bit == 0 {
+			return 0, nil
+		}
+		return -1, nil
+	}
+
+	var sBit, eBit int
+	if byBit {
+		s, e, ok := bitRange(len(data)*8, start, end)
+		if !ok {
+			return -1, nil
+		}
+		sBit, eBit = s, e
+	} else {
+		s, e, ok := bitRange(len(data), start, end)
+		if !ok {
+			return -1, nil
+		}
+		sBit, eBit = s*8, e*8+7
+	}
+
+	for i := sBit; i <= eBit; i++ {
+		if bitAt(data, i) == bit {
+			return int64(i), nil
+		}
+	}
+	// With no explicit end, a clear-bit search treats the string as
+	// right-padded with infinite zero bits, so the bit right after its
+	// content counts as found rather than leaving the search to fail.
+	if bit == 0 && !hasEnd {
+		return int64(len(data) * 8), nil
+	}
+	return -1, nil
+}
+
+// BitOp is the transactional implementation behind DB.BitOp.
+func (tx *Tx) BitOp(op BitOp, destKey string, srcKeys ...string) (int64, error) {
+	if op == BitNot && len(srcKeys) != 1 {
+		return 0, core.ErrValueType
+	}
+
+	// Read each source individually rather than through GetMany, which
+	// documents itself as silently ignoring keys that aren't strings:
+	// BitOp must tell a missing key (treated as empty) apart from one
+	// that exists but holds the wrong type (an error), and GetMany
+	// conflates the two. Get reports a key of another type as not found
+	// too, so such a key is looked up by type to tell the two apart.
+	srcs := make([][]byte, len(srcKeys))
+	maxLen := 0
+	for i, key := range srcKeys {
+		val, err := tx.Get(key)
+		if err == core.ErrNotFound {
+			if _, keyErr := sqlx.GetKeyID(tx.SQL, key, core.TypeString); keyErr != nil && keyErr != core.ErrNotFound {
+				return 0, keyErr
+			}
+		} else if err != nil {
+			return 0, err
+		}
+		if err == nil {
+			srcs[i] = val.Bytes()
+		}
+		if len(srcs[i]) > maxLen {
+			maxLen = len(srcs[i])
+		}
+	}
+
+	result := make([]byte, maxLen)
+	if op == BitNot {
+		src := srcs[0]
+		for i := range result {
+			var b byte
+			if i < len(src) {
+				b = src[i]
+			}
+			result[i] = ^b
+		}
+	} else {
+		for i := 0; i < maxLen; i++ {
+			var b byte
+			for j, src := range srcs {
+				var cur byte
+				if i < len(src) {
+					cur = src[i]
+				}
+				if j == 0 {
+					b = cur
+					continue
+				}
+				switch op {
+				case BitAnd:
+					b &= cur
+				case BitOr:
+					b |= cur
+				case BitXor:
+					b ^= cur
+				}
+			}
+			result[i] = b
+		}
+	}
+
+	if err := tx.Set(destKey, result); err != nil {
+		return 0, err
+	}
+	return int64(len(result)), nil
+}
+
+// Append is the transactional implementation behind DB.Append.
+func (tx *Tx) Append(key string, value []byte) (int, error) {
+	val, err := tx.Get(key)
+	if err != nil && err != core.ErrNotFound {
+		return 0, err
+	}
+	data := append(val.Bytes(), value...)
+	if err := tx.Set(key, data); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// bitAt returns the bit (0 or 1) at bit index i in data, addressed MSB-first
+// within each byte (bit 0 is the 0x80 bit of data[0]).
+func bitAt(data []byte, i int) int {
+	mask := byte(0x80) >> (i % 8)
+	if data[i/8]&mask != 0 {
+		return 1
+	}
+	return 0
+}
+
+// bitRange clamps a Redis-style [start,end] range (negative indexes count
+// from the end) to the inclusive bounds [0,n-1]. Returns ok=false if the
+// range is empty.
+func bitRange(n int, start, end int64) (int, int, bool) {
+	if n == 0 {
+		return 0, 0, false
+	}
+	if start < 0 {
+		start += int64(n)
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end < 0 {
+		end += int64(n)
+	}
+	if end >= int64(n) {
+		end = int64(n) - 1
+	}
+	if start > end || start >= int64(n) {
+		return 0, 0, false
+	}
+	return int(start), int(end), true
+}
@@ -0,0 +1,117 @@
+package rstring
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/nalgeon/redka/internal/core"
+	"github.com/nalgeon/redka/internal/kvdb"
+)
+
+func TestBitAt(t *testing.T) {
+	data := []byte{0b10100000, 0b00000001}
+	tests := []struct {
+		i    int
+		want int
+	}{
+		{0, 1},
+		{1, 0},
+		{2, 1},
+		{7, 0},
+		{15, 1},
+	}
+	for _, tt := range tests {
+		if got := bitAt(data, tt.i); got != tt.want {
+			t.Errorf("bitAt(data, %d) = %d, want %d", tt.i, got, tt.want)
+		}
+	}
+}
+
+func TestBitRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		n          int
+		start, end int64
+		wantS      int
+		wantE      int
+		wantOK     bool
+	}{
+		{"empty string", 0, 0, -1, 0, 0, false},
+		{"full range", 8, 0, -1, 0, 7, true},
+		{"negative start", 8, -3, -1, 5, 7, true},
+		{"negative both clamp", 8, -100, -1, 0, 7, true},
+		{"end beyond length clamps", 8, 0, 100, 0, 7, true},
+		{"start past end is empty", 8, 5, 2, 0, 0, false},
+		{"start at length is empty", 8, 8, 10, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, e, ok := bitRange(tt.n, tt.start, tt.end)
+			if ok != tt.wantOK {
+				t.Fatalf("bitRange(%d, %d, %d) ok = %v, want %v", tt.n, tt.start, tt.end, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if s != tt.wantS || e != tt.wantE {
+				t.Errorf("bitRange(%d, %d, %d) = (%d, %d), want (%d, %d)", tt.n, tt.start, tt.end, s, e, tt.wantS, tt.wantE)
+			}
+		})
+	}
+}
+
+// TestBitPosClearBitWithNoExplicitEndPadsWithZeros guards Redis's
+// documented special case: searching for a clear bit with no explicit
+// end treats the string as right-padded with infinite zero bits, so
+// the position right after an all-ones string is returned instead
+// of -1.
+func TestBitPosClearBitWithNoExplicitEndPadsWithZeros(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	if err := d.Set("key", []byte{0xff, 0xff}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	pos, err := d.BitPos("key", 0, 0, -1, false, false)
+	if err != nil {
+		t.Fatalf("BitPos: %v", err)
+	}
+	if pos != 16 {
+		t.Errorf("BitPos with no explicit end = %d, want 16 (first clear bit past the string)", pos)
+	}
+
+	pos, err = d.BitPos("key", 0, 0, -1, false, true)
+	if err != nil {
+		t.Fatalf("BitPos: %v", err)
+	}
+	if pos != -1 {
+		t.Errorf("BitPos with explicit end = %d, want -1 (no zero-padding beyond an explicit range)", pos)
+	}
+}
+
+// TestBitOpErrorsOnWrongTypeSource guards BitOp's distinction between a
+// missing source key (treated as empty) and one that exists but isn't
+// a string (an error): unlike GetMany, which silently treats both the
+// same way, BitOp must surface the latter as ErrKeyType rather than
+// treating the key as all-zero bytes.
+func TestBitOpErrorsOnWrongTypeSource(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	if err := d.Set("str", []byte{0xff}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	sqlDB := backend.(kvdb.SQLCapable).SQL().(*sql.DB)
+	if _, err := sqlDB.Exec(`insert into rkey (key, type) values (?, ?)`, "notstr", core.TypeSet); err != nil {
+		t.Fatalf("seeding a non-string rkey row for notstr: %v", err)
+	}
+
+	_, err := d.BitOp(BitAnd, "dest", "str", "notstr", "missing")
+	if err != core.ErrKeyType {
+		t.Fatalf("BitOp with a wrong-type source error = %v, want %v", err, core.ErrKeyType)
+	}
+}
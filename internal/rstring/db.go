@@ -3,10 +3,10 @@
 package rstring
 
 import (
-	"database/sql"
 	"time"
 
 	"github.com/nalgeon/redka/internal/core"
+	"github.com/nalgeon/redka/internal/kvdb"
 	"github.com/nalgeon/redka/internal/sqlx"
 )
 
@@ -17,10 +17,10 @@ type DB struct {
 	*sqlx.DB[*Tx]
 }
 
-// New connects to the string repository.
+// New connects to the string repository using the given storage backend.
 // Does not create the database schema.
-func New(db *sql.DB) *DB {
-	d := sqlx.New(db, NewTx)
+func New(backend kvdb.Backend) *DB {
+	d := sqlx.New(backend, NewTx)
 	return &DB{d}
 }
 
@@ -49,7 +49,14 @@ func (d *DB) GetSet(key string, value any, ttl time.Duration) (core.Value, error
 	err := d.Update(func(tx *Tx) error {
 		var err error
 		val, err = tx.GetSet(key, value, ttl)
-		return err
+		if err != nil {
+			return err
+		}
+		newVal, err := tx.Get(key)
+		if err != nil {
+			return err
+		}
+		return tx.reindex(key, newVal, true)
 	})
 	return val, err
 }
@@ -89,7 +96,14 @@ func (d *DB) IncrFloat(key string, delta float64) (float64, error) {
 // If the key exists but is not a string, returns ErrKeyType.
 func (d *DB) Set(key string, value any) error {
 	err := d.Update(func(tx *Tx) error {
-		return tx.Set(key, value)
+		if err := tx.Set(key, value); err != nil {
+			return err
+		}
+		newVal, err := tx.Get(key)
+		if err != nil {
+			return err
+		}
+		return tx.reindex(key, newVal, true)
 	})
 	return err
 }
@@ -103,7 +117,14 @@ func (d *DB) SetExists(key string, value any, ttl time.Duration) (bool, error) {
 	err := d.Update(func(tx *Tx) error {
 		var err error
 		ok, err = tx.SetExists(key, value, ttl)
-		return err
+		if err != nil || !ok {
+			return err
+		}
+		newVal, err := tx.Get(key)
+		if err != nil {
+			return err
+		}
+		return tx.reindex(key, newVal, true)
 	})
 	return ok, err
 }
@@ -113,7 +134,14 @@ func (d *DB) SetExists(key string, value any, ttl time.Duration) (bool, error) {
 // If the key exists but is not a string, returns ErrKeyType.
 func (d *DB) SetExpires(key string, value any, ttl time.Duration) error {
 	err := d.Update(func(tx *Tx) error {
-		return tx.SetExpires(key, value, ttl)
+		if err := tx.SetExpires(key, value, ttl); err != nil {
+			return err
+		}
+		newVal, err := tx.Get(key)
+		if err != nil {
+			return err
+		}
+		return tx.reindex(key, newVal, true)
 	})
 	return err
 }
@@ -125,7 +153,19 @@ func (d *DB) SetExpires(key string, value any, ttl time.Duration) error {
 // If any of the keys exists but is not a string, returns ErrKeyType.
 func (d *DB) SetMany(items map[string]any) error {
 	err := d.Update(func(tx *Tx) error {
-		return tx.SetMany(items)
+		if err := tx.SetMany(items); err != nil {
+			return err
+		}
+		for key := range items {
+			newVal, err := tx.Get(key)
+			if err != nil {
+				return err
+			}
+			if err := tx.reindex(key, newVal, true); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 	return err
 }
@@ -139,7 +179,19 @@ func (d *DB) SetManyNX(items map[string]any) (bool, error) {
 	err := d.Update(func(tx *Tx) error {
 		var err error
 		ok, err = tx.SetManyNX(items)
-		return err
+		if err != nil || !ok {
+			return err
+		}
+		for key := range items {
+			newVal, err := tx.Get(key)
+			if err != nil {
+				return err
+			}
+			if err := tx.reindex(key, newVal, true); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 	return ok, err
 }
@@ -153,7 +205,14 @@ func (d *DB) SetNotExists(key string, value any, ttl time.Duration) (bool, error
 	err := d.Update(func(tx *Tx) error {
 		var err error
 		ok, err = tx.SetNotExists(key, value, ttl)
-		return err
+		if err != nil || !ok {
+			return err
+		}
+		newVal, err := tx.Get(key)
+		if err != nil {
+			return err
+		}
+		return tx.reindex(key, newVal, true)
 	})
 	return ok, err
 }
@@ -0,0 +1,632 @@
+package rstring
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+
+	"github.com/nalgeon/redka/internal/core"
+)
+
+// projKind identifies what part of a string value an index's
+// Projection extracts as the sort key.
+type projKind int
+
+const (
+	projWhole projKind = iota
+	projJSONPath
+	projSpatial
+)
+
+// Projection selects what part of a string value is used as an
+// index's sort key. Build one with Whole, JSONPath or Spatial.
+// Its fields are exported only so CreateIndex can persist them as
+// JSON; construct a Projection with the functions below, not with a
+// struct literal.
+type Projection struct {
+	Kind  projKind `json:"kind"`
+	Path  string   `json:"path,omitempty"`
+	Path2 string   `json:"path2,omitempty"`
+}
+
+// Whole indexes the entire value as-is.
+func Whole() Projection {
+	return Projection{Kind: projWhole}
+}
+
+// JSONPath indexes the value at the given dot-separated path within a
+// JSON-encoded string, e.g. JSONPath("user.age"). Values that are not
+// valid JSON, or that don't have the given path, are excluded from
+// the index.
+func JSONPath(path string) Projection {
+	return Projection{Kind: projJSONPath, Path: path}
+}
+
+// Spatial indexes a 2D point read from the latPath/lonPath JSON fields.
+// The sort key is the text of the two values joined by a comma
+// ("lat,lon"), so a String comparator orders entries by that text, not
+// numerically; with an Int or Float comparator the joined key does not
+// parse and the value is left out of the index.
+func Spatial(latPath, lonPath string) Projection {
+	return Projection{Kind: projSpatial, Path: latPath, Path2: lonPath}
+}
+
+// cmpKind identifies how an index's Comparator orders sort keys.
+type cmpKind int
+
+const (
+	cmpString cmpKind = iota
+	cmpInt
+	cmpFloat
+)
+
+// Comparator determines how two sort keys of an index are ordered.
+// Build one with String, Int or Float, and optionally wrap it in Desc.
+// Its fields are exported only so CreateIndex can persist them as
+// JSON; construct a Comparator with the functions below, not with a
+// struct literal.
+type Comparator struct {
+	Kind cmpKind `json:"kind"`
+	Desc bool    `json:"desc,omitempty"`
+}
+
+// String orders sort keys lexicographically. This is the default.
+func String() Comparator { return Comparator{Kind: cmpString} }
+
+// Int orders sort keys as 64-bit integers.
+func Int() Comparator { return Comparator{Kind: cmpInt} }
+
+// Float orders sort keys as 64-bit floats.
+func Float() Comparator { return Comparator{Kind: cmpFloat} }
+
+// Desc reverses the order of the given comparator.
+func Desc(c Comparator) Comparator {
+	c.Desc = true
+	return c
+}
+
+// IndexOptions configures an index created by DB.CreateIndex.
+type IndexOptions struct {
+	Projection Projection
+	Comparator Comparator
+}
+
+// CreateIndex creates a secondary index named name over the string
+// keys matching pattern (a key glob, as accepted by Scan). opts
+// selects what part of each matching value to index and how to order
+// it. Keys matching pattern that already exist are indexed
+// immediately; from this point on, matching keys are (re)indexed
+// every time they are written via Set, SetMany, SetExists, SetNotExists
+// or GetSet.
+// If an index named name already exists, returns ErrIndexExists.
+func (d *DB) CreateIndex(name, pattern string, opts IndexOptions) error {
+	err := d.Update(func(tx *Tx) error {
+		return tx.CreateIndex(name, pattern, opts)
+	})
+	return err
+}
+
+// DropIndex removes the index named name and its materialized data.
+// If the index does not exist, returns core.ErrNotFound.
+func (d *DB) DropIndex(name string) error {
+	err := d.Update(func(tx *Tx) error {
+		return tx.DropIndex(name)
+	})
+	return err
+}
+
+// Indexes returns the names of all indexes, in creation order.
+func (d *DB) Indexes() ([]string, error) {
+	tx := NewTx(d.SQL)
+	return tx.Indexes()
+}
+
+// Ascend calls fn for every key in the index named name, in ascending
+// sort-key order, starting at pivot (inclusive), or from the first
+// entry if pivot is empty. Stops early if fn returns false.
+func (d *DB) Ascend(name, pivot string, fn func(key string, val core.Value) bool) error {
+	tx := NewTx(d.SQL)
+	return tx.Ascend(name, pivot, fn)
+}
+
+// Descend is like Ascend, but iterates in descending sort-key order.
+func (d *DB) Descend(name, pivot string, fn func(key string, val core.Value) bool) error {
+	tx := NewTx(d.SQL)
+	return tx.Descend(name, pivot, fn)
+}
+
+// AscendRange calls fn for every key in the index named name whose
+// sort key falls within [start,end), in the index's natural order
+// (ascending for a plain comparator, descending for one wrapped in
+// Desc). start and end are always given in that same order: start is
+// the first value that would be visited, end the exclusive bound that
+// stops iteration, regardless of comparator direction. Stops early if
+// fn returns false.
+func (d *DB) AscendRange(name, start, end string, fn func(key string, val core.Value) bool) error {
+	tx := NewTx(d.SQL)
+	return tx.AscendRange(name, start, end, fn)
+}
+
+// CreateIndex is the transactional implementation behind DB.CreateIndex.
+func (tx *Tx) CreateIndex(name, pattern string, opts IndexOptions) error {
+	var exists int
+	err := tx.SQL.QueryRow(sqlIndexExists, name).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists > 0 {
+		return ErrIndexExists
+	}
+
+	projJSON, err := json.Marshal(opts.Projection)
+	if err != nil {
+		return err
+	}
+	cmpJSON, err := json.Marshal(opts.Comparator)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.SQL.Exec(sqlIndexCreate, name, pattern, projJSON, cmpJSON); err != nil {
+		return err
+	}
+	return tx.backfillIndex(name, pattern, opts)
+}
+
+// backfillIndex indexes every existing string key matching pattern,
+// so an index created over pre-existing data is immediately queryable
+// instead of only filling in as matching keys happen to be rewritten.
+func (tx *Tx) backfillIndex(name, pattern string, opts IndexOptions) error {
+	rows, err := tx.SQL.Query(sqlStringKeys, core.TypeString)
+	if err != nil {
+		return err
+	}
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return err
+		}
+		matched, err := path.Match(pattern, key)
+		if err != nil || !matched {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		val, err := tx.Get(key)
+		if err != nil {
+			if err == core.ErrNotFound {
+				continue
+			}
+			return err
+		}
+		sortKey, indexable := sortKeyFor(opts.Projection, opts.Comparator, val)
+		if !indexable {
+			continue
+		}
+		if _, err := tx.SQL.Exec(sqlIndexDataInsert, name, sortKey, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropIndex is the transactional implementation behind DB.DropIndex.
+func (tx *Tx) DropIndex(name string) error {
+	res, err := tx.SQL.Exec(sqlIndexDrop, name)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return core.ErrNotFound
+	}
+	_, err = tx.SQL.Exec(sqlIndexDataDrop, name)
+	return err
+}
+
+// Indexes is the transactional implementation behind DB.Indexes.
+func (tx *Tx) Indexes() ([]string, error) {
+	rows, err := tx.SQL.Query(sqlIndexNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// Ascend is the transactional implementation behind DB.Ascend.
+func (tx *Tx) Ascend(name, pivot string, fn func(key string, val core.Value) bool) error {
+	cmp, err := tx.indexComparator(name)
+	if err != nil {
+		return err
+	}
+	encoded, ok := encodeSortKey(cmp, pivot)
+	if !ok {
+		return core.ErrValueType
+	}
+	return tx.walkIndex(name, encoded, "", false, fn)
+}
+
+// Descend is the transactional implementation behind DB.Descend.
+func (tx *Tx) Descend(name, pivot string, fn func(key string, val core.Value) bool) error {
+	cmp, err := tx.indexComparator(name)
+	if err != nil {
+		return err
+	}
+	encoded, ok := encodeSortKey(cmp, pivot)
+	if !ok {
+		return core.ErrValueType
+	}
+	return tx.walkIndex(name, encoded, "", true, fn)
+}
+
+// AscendRange is the transactional implementation behind DB.AscendRange.
+func (tx *Tx) AscendRange(name, start, end string, fn func(key string, val core.Value) bool) error {
+	cmp, err := tx.indexComparator(name)
+	if err != nil {
+		return err
+	}
+	encodedStart, ok := encodeSortKey(cmp, start)
+	if !ok {
+		return core.ErrValueType
+	}
+	encodedEnd, ok := encodeSortKey(cmp, end)
+	if !ok {
+		return core.ErrValueType
+	}
+	// encodeSortKey complements the sort key for a Desc comparator, so the
+	// caller's [start, end) in comparator order is already
+	// [encodedStart, encodedEnd) in the materialized ascending order
+	// that walkIndex scans.
+	return tx.walkIndex(name, encodedStart, encodedEnd, false, fn)
+}
+
+// indexComparator looks up the Comparator that the named index was
+// created with, so pivots passed to Ascend/Descend/AscendRange can be
+// encoded the same way the indexed values are.
+func (tx *Tx) indexComparator(name string) (Comparator, error) {
+	var cmpJSON []byte
+	err := tx.SQL.QueryRow(sqlIndexComparator, name).Scan(&cmpJSON)
+	if err == sql.ErrNoRows {
+		return Comparator{}, core.ErrNotFound
+	}
+	if err != nil {
+		return Comparator{}, err
+	}
+	var cmp Comparator
+	if err := json.Unmarshal(cmpJSON, &cmp); err != nil {
+		return Comparator{}, err
+	}
+	return cmp, nil
+}
+
+// walkIndex iterates the materialized (sort_key, key) pairs of the
+// named index in sort-key order, resolves each key's current value
+// and calls fn, stopping early if fn returns false. pivot and end are
+// already-encoded sort keys (see encodeSortKey), not raw user input;
+// an empty pivot means "from the first/last entry", an empty end
+// means "no upper bound".
+func (tx *Tx) walkIndex(name, pivot, end string, desc bool, fn func(key string, val core.Value) bool) error {
+	var query string
+	switch {
+	case desc && pivot == "":
+		query = sqlIndexDescendAll
+	case desc:
+		query = sqlIndexDescendFrom
+	case pivot == "":
+		query = sqlIndexAscendAll
+	default:
+		query = sqlIndexAscendFrom
+	}
+
+	var rows *sql.Rows
+	var err error
+	if pivot == "" {
+		rows, err = tx.SQL.Query(query, name)
+	} else {
+		rows, err = tx.SQL.Query(query, name, pivot)
+	}
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sortKey, key string
+		if err := rows.Scan(&sortKey, &key); err != nil {
+			return err
+		}
+		if end != "" && sortKey >= end {
+			break
+		}
+		val, err := tx.Get(key)
+		if err == core.ErrNotFound {
+			// key was deleted or expired after being indexed (see the
+			// reindex doc comment below) without its rindex_data row
+			// being cleaned up; skip it rather than surfacing a key
+			// that no longer exists.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !fn(key, val) {
+			break
+		}
+	}
+	return rows.Err()
+}
+
+// reindex refreshes every index entry for key after its value changed
+// to newVal (or was removed, if ok is false). It is called from within
+// the same transaction as Set, SetMany, SetExists, SetNotExists and
+// GetSet, so index and data stay consistent across those calls.
+//
+// It is not, and cannot yet be, called when key is deleted or expires:
+// this DB has no Delete method of its own (key deletion and expiration
+// live in the rkey repository, which this package doesn't depend on),
+// so there is no transactional hook to call reindex(key, nil, false)
+// from. Until rkey's delete/expire path grows one, walkIndex instead
+// tolerates the resulting stale rindex_data rows by skipping any key
+// that no longer resolves (see its core.ErrNotFound handling above),
+// rather than returning a phantom zero-value result for it.
+func (tx *Tx) reindex(key string, newVal core.Value, ok bool) error {
+	rows, err := tx.SQL.Query(sqlIndexMatching, key)
+	if err != nil {
+		return err
+	}
+	type indexDef struct {
+		name string
+		proj Projection
+		cmp  Comparator
+	}
+	var defs []indexDef
+	for rows.Next() {
+		var name, pattern string
+		var projJSON, cmpJSON []byte
+		if err := rows.Scan(&name, &pattern, &projJSON, &cmpJSON); err != nil {
+			rows.Close()
+			return err
+		}
+		matched, err := path.Match(pattern, key)
+		if err != nil || !matched {
+			continue
+		}
+		var proj Projection
+		var cmp Comparator
+		if err := json.Unmarshal(projJSON, &proj); err != nil {
+			continue
+		}
+		if err := json.Unmarshal(cmpJSON, &cmp); err != nil {
+			continue
+		}
+		defs = append(defs, indexDef{name, proj, cmp})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, def := range defs {
+		if _, err := tx.SQL.Exec(sqlIndexDataRemove, def.name, key); err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		sortKey, indexable := sortKeyFor(def.proj, def.cmp, newVal)
+		if !indexable {
+			continue
+		}
+		if _, err := tx.SQL.Exec(sqlIndexDataInsert, def.name, sortKey, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortKeyFor extracts and encodes val's sort key according to proj and
+// cmp, so that ordinary string ordering on the result matches cmp's
+// intended order. Returns ok=false if val cannot be indexed (e.g. not
+// valid JSON for a JSONPath/Spatial projection).
+func sortKeyFor(proj Projection, cmp Comparator, val core.Value) (sortKey string, ok bool) {
+	raw := val.String()
+
+	switch proj.Kind {
+	case projWhole:
+		// raw as-is
+	case projJSONPath:
+		var doc any
+		if err := json.Unmarshal(val.Bytes(), &doc); err != nil {
+			return "", false
+		}
+		v, found := jsonPathValue(doc, proj.Path)
+		if !found {
+			return "", false
+		}
+		raw = valueToString(v)
+	case projSpatial:
+		var doc any
+		if err := json.Unmarshal(val.Bytes(), &doc); err != nil {
+			return "", false
+		}
+		lat, ok1 := jsonPathValue(doc, proj.Path)
+		lon, ok2 := jsonPathValue(doc, proj.Path2)
+		if !ok1 || !ok2 {
+			return "", false
+		}
+		raw = valueToString(lat) + "," + valueToString(lon)
+	}
+
+	return encodeSortKey(cmp, raw)
+}
+
+// encodeSortKey converts a raw, unencoded string into the same
+// representation sortKeyFor stores for that comparator, so that
+// Ascend/Descend/AscendRange can encode a caller-supplied pivot or
+// range bound the same way the materialized sort keys were encoded.
+// An empty raw value is left as "" (the "no bound" sentinel used by
+// walkIndex), regardless of comparator.
+func encodeSortKey(cmp Comparator, raw string) (sortKey string, ok bool) {
+	if raw == "" {
+		return "", true
+	}
+
+	switch cmp.Kind {
+	case cmpInt:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return "", false
+		}
+		sortKey = sortableInt(n)
+	case cmpFloat:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return "", false
+		}
+		sortKey = sortableFloat(f)
+	default:
+		sortKey = raw
+	}
+
+	if cmp.Desc {
+		sortKey = reverseSortKey(sortKey)
+	}
+	return sortKey, true
+}
+
+// jsonPathValue resolves a dot-separated path (e.g. "user.age")
+// against a decoded JSON document.
+func jsonPathValue(doc any, path string) (any, bool) {
+	cur := doc
+	for _, part := range splitPath(path) {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		cur, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func splitPath(p string) []string {
+	if p == "" {
+		return nil
+	}
+	var parts []string
+	start := 0
+	for i := 0; i < len(p); i++ {
+		if p[i] == '.' {
+			parts = append(parts, p[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, p[start:])
+}
+
+func valueToString(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	default:
+		b, _ := json.Marshal(x)
+		return string(b)
+	}
+}
+
+// sortKeyWidth is the number of decimal digits in the largest uint64
+// (18446744073709551615), so zero-padding to this width makes
+// lexicographic ordering of sortableInt/sortableFloat output match
+// numeric ordering across the whole range, not just within a fixed
+// digit count.
+const sortKeyWidth = 20
+
+// sortableInt encodes n so that lexicographic ordering of the result
+// matches numeric ordering: offset into the unsigned range by
+// flipping the sign bit, then zero-pad to sortKeyWidth digits.
+func sortableInt(n int64) string {
+	u := uint64(n) ^ (1 << 63)
+	return fmt.Sprintf("%0*d", sortKeyWidth, u)
+}
+
+// sortableFloat encodes f so that lexicographic ordering of the
+// result matches numeric ordering: take the IEEE-754 bit pattern,
+// then flip the sign bit for non-negative numbers (so they sort
+// after negatives) or flip every bit for negative numbers (so more
+// negative numbers sort first), and zero-pad to sortKeyWidth digits.
+func sortableFloat(f float64) string {
+	bits := math.Float64bits(f)
+	if bits&(1<<63) != 0 {
+		bits = ^bits
+	} else {
+		bits |= 1 << 63
+	}
+	return fmt.Sprintf("%0*d", sortKeyWidth, bits)
+}
+
+// reverseSortKey complements every byte of a sortable key so that an
+// ascending scan over the complemented keys visits entries in the
+// original descending order. Unlike a digit-specific transform, this
+// works for any byte sequence, including raw (non-numeric) String
+// sort keys.
+func reverseSortKey(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		b[i] = ^c
+	}
+	return string(b)
+}
+
+// SQL queries for index metadata (rindex) and materialized, sorted
+// index entries (rindex_data), keyed by (index_name, sort_key, key).
+const (
+	sqlStringKeys       = `select key from rkey where type = ?`
+	sqlIndexExists      = `select count(*) from rindex where name = ?`
+	sqlIndexCreate      = `insert into rindex (name, pattern, projection, comparator) values (?, ?, ?, ?)`
+	sqlIndexDrop        = `delete from rindex where name = ?`
+	sqlIndexDataDrop    = `delete from rindex_data where index_name = ?`
+	sqlIndexNames       = `select name from rindex order by rowid`
+	sqlIndexMatching    = `select name, pattern, projection, comparator from rindex`
+	sqlIndexComparator  = `select comparator from rindex where name = ?`
+	sqlIndexDataRemove  = `delete from rindex_data where index_name = ? and key = ?`
+	sqlIndexDataInsert  = `insert into rindex_data (index_name, sort_key, key) values (?, ?, ?)`
+	sqlIndexAscendAll   = `select sort_key, key from rindex_data where index_name = ? order by sort_key asc`
+	sqlIndexAscendFrom  = `select sort_key, key from rindex_data where index_name = ? and sort_key >= ? order by sort_key asc`
+	sqlIndexDescendAll  = `select sort_key, key from rindex_data where index_name = ? order by sort_key desc`
+	sqlIndexDescendFrom = `select sort_key, key from rindex_data where index_name = ? and sort_key <= ? order by sort_key desc`
+)
+
+// ErrIndexExists is returned by CreateIndex when an index with the
+// given name already exists.
+var ErrIndexExists = errors.New("rstring: index already exists")
@@ -0,0 +1,241 @@
+package rstring
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/nalgeon/redka/internal/core"
+	"github.com/nalgeon/redka/internal/kvdb"
+)
+
+// TestProjectionComparatorJSONRoundTrip guards against the fields
+// silently going unexported again: if they do, every index degrades
+// to Whole()+String() because CreateIndex marshals {} and reindex
+// unmarshals it back to the zero value.
+func TestProjectionComparatorJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		proj Projection
+	}{
+		{"whole", Whole()},
+		{"json path", JSONPath("user.age")},
+		{"spatial", Spatial("lat", "lon")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.proj)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var got Projection
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got != tt.proj {
+				t.Errorf("round-trip = %+v, want %+v (raw JSON: %s)", got, tt.proj, data)
+			}
+		})
+	}
+
+	cmps := []Comparator{String(), Int(), Float(), Desc(Int()), Desc(String())}
+	for _, cmp := range cmps {
+		data, err := json.Marshal(cmp)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		var got Comparator
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if got != cmp {
+			t.Errorf("round-trip = %+v, want %+v (raw JSON: %s)", got, cmp, data)
+		}
+	}
+}
+
+func TestSortableIntOrder(t *testing.T) {
+	ns := []int64{-1 << 62, -1000, -1, 0, 1, 1000, 1 << 62}
+	keys := make([]string, len(ns))
+	for i, n := range ns {
+		keys[i] = sortableInt(n)
+	}
+	if !sort.StringsAreSorted(keys) {
+		t.Fatalf("sortableInt keys not in ascending lexicographic order for %v: %v", ns, keys)
+	}
+	for _, k := range keys {
+		if len(k) != sortKeyWidth {
+			t.Errorf("sortableInt(%s) has width %d, want %d", k, len(k), sortKeyWidth)
+		}
+	}
+}
+
+func TestSortableFloatOrder(t *testing.T) {
+	fs := []float64{-1e12, -1.5, -0.0001, 0, 0.0001, 1.5, 1e12}
+	keys := make([]string, len(fs))
+	for i, f := range fs {
+		keys[i] = sortableFloat(f)
+	}
+	if !sort.StringsAreSorted(keys) {
+		t.Fatalf("sortableFloat keys not in ascending lexicographic order for %v: %v", fs, keys)
+	}
+	for _, k := range keys {
+		if len(k) != sortKeyWidth {
+			t.Errorf("sortableFloat(%s) has width %d, want %d", k, len(k), sortKeyWidth)
+		}
+	}
+}
+
+func TestReverseSortKeyInvertsArbitraryBytes(t *testing.T) {
+	in := []string{"abc", "\x00\x01\xff", sortableInt(42)}
+	for _, s := range in {
+		rev := reverseSortKey(s)
+		if len(rev) != len(s) {
+			t.Fatalf("reverseSortKey(%q) changed length: got %d, want %d", s, len(rev), len(s))
+		}
+		if reverseSortKey(rev) != s {
+			t.Errorf("reverseSortKey is not its own inverse for %q", s)
+		}
+	}
+
+	// Ascending order over the reversed keys must match descending
+	// order over the originals.
+	words := []string{"alpha", "beta", "gamma"}
+	reversed := make([]string, len(words))
+	for i, w := range words {
+		reversed[i] = reverseSortKey(w)
+	}
+	sort.Strings(words)
+	sort.Sort(sort.Reverse(sort.StringSlice(words)))
+	sortedReversed := make([]string, len(reversed))
+	copy(sortedReversed, reversed)
+	sort.Strings(sortedReversed)
+	for i := range sortedReversed {
+		if reverseSortKey(sortedReversed[i]) != words[i] {
+			t.Fatalf("ascending order over reversed keys does not match descending original order at %d", i)
+		}
+	}
+}
+
+func TestCreateIndexBackfillsExistingKeys(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	if err := d.Set("user:1", `{"age":30}`); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := d.Set("user:2", `{"age":20}`); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := d.Set("other:1", `{"age":99}`); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	err := d.CreateIndex("by-age", "user:*", IndexOptions{
+		Projection: JSONPath("age"),
+		Comparator: Int(),
+	})
+	if err != nil {
+		t.Fatalf("CreateIndex: %v", err)
+	}
+
+	var keys []string
+	err = d.Ascend("by-age", "", func(key string, val core.Value) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Ascend: %v", err)
+	}
+	want := []string{"user:2", "user:1"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("Ascend after CreateIndex over pre-existing data = %v, want %v (index was not backfilled)", keys, want)
+	}
+}
+
+func TestAscendRangeWithDescComparator(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	for key, val := range map[string]string{"k10": "10", "k20": "20", "k30": "30", "k40": "40"} {
+		if err := d.Set(key, val); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+	err := d.CreateIndex("by-val-desc", "k*", IndexOptions{Comparator: Desc(Int())})
+	if err != nil {
+		t.Fatalf("CreateIndex: %v", err)
+	}
+
+	var got []string
+	err = d.AscendRange("by-val-desc", "30", "10", func(key string, val core.Value) bool {
+		got = append(got, val.String())
+		return true
+	})
+	if err != nil {
+		t.Fatalf("AscendRange: %v", err)
+	}
+	want := []string{"30", "20"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("AscendRange(\"30\", \"10\", ...) on a Desc index = %v, want %v (start/end given in the index's natural, descending order)", got, want)
+	}
+}
+
+// TestWalkIndexSkipsStaleEntries guards walkIndex against surfacing a
+// phantom key: rstring has no Delete method to hook reindex from (see
+// reindex's doc comment), so a stale rindex_data row — simulated here
+// directly, the way a deleted or expired indexed key would leave one
+// behind — must be skipped rather than returned with a zero value.
+func TestWalkIndexSkipsStaleEntries(t *testing.T) {
+	backend, cleanup := kvdb.GetTestBackend()
+	defer cleanup()
+	d := New(backend)
+
+	if err := d.Set("k1", "1"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := d.Set("k2", "2"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := d.CreateIndex("idx", "k*", IndexOptions{Comparator: Int()}); err != nil {
+		t.Fatalf("CreateIndex: %v", err)
+	}
+
+	sqlDB := backend.(kvdb.SQLCapable).SQL().(*sql.DB)
+	if _, err := sqlDB.Exec(`delete from rkey where key = ?`, "k1"); err != nil {
+		t.Fatalf("simulating a deleted indexed key: %v", err)
+	}
+
+	var keys []string
+	err := d.Ascend("idx", "", func(key string, val core.Value) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Ascend: %v", err)
+	}
+	want := []string{"k2"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("Ascend with a stale index entry = %v, want %v (phantom key surfaced)", keys, want)
+	}
+}
+
+func TestEncodeSortKeyMatchesSortKeyFor(t *testing.T) {
+	cmp := Desc(Int())
+	raw := "42"
+	encoded, ok := encodeSortKey(cmp, raw)
+	if !ok {
+		t.Fatal("encodeSortKey returned ok=false")
+	}
+	want, ok := sortKeyFor(Whole(), cmp, core.Value(raw))
+	if !ok {
+		t.Fatal("sortKeyFor returned ok=false")
+	}
+	if encoded != want {
+		t.Errorf("encodeSortKey(%v, %q) = %q, want %q (must match sortKeyFor so pivots are meaningful)", cmp, raw, encoded, want)
+	}
+}